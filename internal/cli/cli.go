@@ -1,112 +1,421 @@
 package cli
 
 import (
+	"context"
 	"crypto/sha1" // nolint: gosec
 	"encoding/hex"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"os/signal"
+	"path"
 	"path/filepath"
 	"strings"
+	"syscall"
+	"time"
 
+	"github.com/fatih/color"
 	"github.com/mattn/go-colorable"
 
 	"go.nhat.io/vanityrender/internal/config"
 	"go.nhat.io/vanityrender/internal/git"
 	"go.nhat.io/vanityrender/internal/github"
+	"go.nhat.io/vanityrender/internal/goproxy"
+	"go.nhat.io/vanityrender/internal/module"
 	"go.nhat.io/vanityrender/internal/service/sitecache"
+	"go.nhat.io/vanityrender/internal/service/sitefallback"
 	"go.nhat.io/vanityrender/internal/service/sitefragment"
+	"go.nhat.io/vanityrender/internal/service/siteinfo"
+	"go.nhat.io/vanityrender/internal/service/siteproxy"
 	"go.nhat.io/vanityrender/internal/site"
+	"go.nhat.io/vanityrender/internal/vcs"
 	"go.nhat.io/vanityrender/templates"
 )
 
+const (
+	defaultCloneTimeout = 5 * time.Minute
+	defaultCloneRetries = 3
+)
+
+const (
+	// strategyClone clones the repositories to find the modules.
+	strategyClone = "clone"
+	// strategyRemote lists the remote tags to find the modules, and clones only when the tags are not enough.
+	strategyRemote = "remote"
+	// strategyProxy queries the Go module proxies set by GOPROXY, and clones only the modules that must be fetched directly.
+	strategyProxy = "proxy"
+)
+
+const (
+	exitCodeOK = iota
+	exitCodeError
+	// exitCodeStaleData indicates that the site is rendered but some repositories use their previously published data.
+	exitCodeStaleData
+)
+
+type options struct {
+	configFile    string
+	homepageTpl   string
+	notFoundTpl   string
+	repositoryTpl string
+	moduleTpl     string
+	templatesDir  string
+	outputPath    string
+	modules       []string
+	cloneTimeout  time.Duration
+	cloneRetries  int
+	strategy      string
+	modProxyDir   string
+	modProxyURL   string
+
+	continueOnError    bool
+	skipInvalidModules bool
+}
+
 // Execute is the entrypoint for the cli.
 func Execute() int {
 	var (
-		configFile  string
-		homepageTpl string
-		outputPath  string
-		modulesVal  string
-		noColor     bool
+		opts       options
+		modulesVal string
+		noColor    bool
 	)
 
-	flag.StringVar(&configFile, "config", "config.json", "config file")
-	flag.StringVar(&homepageTpl, "homepage-tpl", "", "template file")
-	flag.StringVar(&outputPath, "out", "build", "output path")
+	flag.StringVar(&opts.configFile, "config", "config.json", "config file")
+	flag.StringVar(&opts.homepageTpl, "homepage-tpl", "", "homepage template file")
+	flag.StringVar(&opts.notFoundTpl, "notfound-tpl", "", "404 template file")
+	flag.StringVar(&opts.repositoryTpl, "repository-tpl", "", "repository template file")
+	flag.StringVar(&opts.moduleTpl, "module-tpl", "", "module landing page template file, used when the landing pages are enabled in the config")
+	flag.StringVar(&opts.templatesDir, "templates-dir", "", "directory of templates that override the embedded ones with the same file names")
+	flag.StringVar(&opts.outputPath, "out", "build", "output path")
 	flag.StringVar(&modulesVal, "modules", "", "rebuild only the listed modules, comma separated")
+	flag.DurationVar(&opts.cloneTimeout, "clone-timeout", defaultCloneTimeout, "timeout for fetching a repository, including retries")
+	flag.IntVar(&opts.cloneRetries, "clone-retries", defaultCloneRetries, "number of retries when fetching a repository fails with a transient error")
+	flag.StringVar(&opts.strategy, "strategy", strategyClone, "how to find the modules of a repository: clone, remote (all the tags, no go.mod check, no README/LICENSE description) or proxy (GOPROXY, no checksum verification)")
+	flag.StringVar(&opts.modProxyDir, "mod-proxy", "", "write a static module proxy into this subdirectory of the output path and point the go-import tags to it")
+	flag.StringVar(&opts.modProxyURL, "mod-proxy-url", "", "URL of the static module proxy in the go-import tags (default https://<host>/<mod-proxy>)")
+	flag.BoolVar(&opts.continueOnError, "continue-on-error", false, "use the previously published data of the repositories that could not be fetched")
+	flag.BoolVar(&opts.skipInvalidModules, "skip-invalid-modules", false, "skip the modules whose go.mod files are invalid instead of failing their repositories")
 	flag.BoolVar(&noColor, "no-color", false, "do not use colors in output")
 
 	flag.Parse()
 
-	modules := split(strings.Trim(modulesVal, "\r\n "), ",")
+	opts.modules = split(strings.Trim(modulesVal, "\r\n "), ",")
 
 	out := colorable.NewNonColorable(os.Stdout)
 	if !noColor {
 		out = colorable.NewColorable(os.Stdout)
 	}
 
-	err := runRender(out, configFile, homepageTpl, outputPath, modules)
-	if err != nil {
-		_, _ = fmt.Fprintf(os.Stderr, "%s\n", err)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-		return 1
+	err := runRender(ctx, out, opts)
+	if err == nil {
+		return exitCodeOK
 	}
 
-	return 0
+	printError(os.Stderr, err)
+
+	if errors.Is(err, site.ErrStaleData) {
+		return exitCodeStaleData
+	}
+
+	return exitCodeError
 }
 
-func runRender(out io.Writer, configFile string, homepageTpl string, outputPath string, modules []string) error {
-	checksum, err := checksum(configFile)
+func runRender(ctx context.Context, out io.Writer, opts options) error {
+	cloner := git.NewCachedCloner(git.NewRetryCloner(git.ClonerFunc(git.Clone), git.WithMaxRetries(opts.cloneRetries)))
+
+	finder, err := initModuleFinder(out, cloner, opts)
 	if err != nil {
 		return err
 	}
 
-	homepageSrc, err := initHomepageSrc(homepageTpl)
+	checksum, err := checksum(opts.configFile)
 	if err != nil {
 		return err
 	}
 
-	outputPath, err = initOutputDir(outputPath)
+	cfg, err := config.FromFile(opts.configFile)
 	if err != nil {
 		return err
 	}
 
-	siteCfg, err := initSiteConfig(out, configFile, checksum, modules)
+	tpls, err := initTemplates(cfg.Templates, cfg.LandingPages, opts)
 	if err != nil {
 		return err
 	}
 
-	r, err := initRenderer(out, homepageSrc, outputPath, checksum)
+	outputPath, err := initOutputDir(opts.outputPath)
 	if err != nil {
 		return err
 	}
 
-	return r.Render(*siteCfg)
+	// The templates are parsed before fetching the repositories, so their errors are reported right away.
+	r, err := initRenderer(out, cloner, tpls, outputPath, checksum, opts)
+	if err != nil {
+		return err
+	}
+
+	siteCfg, hydrateErr := initSiteConfig(ctx, out, cloner, finder, checksum, cfg, opts)
+	if hydrateErr != nil && !errors.Is(hydrateErr, site.ErrStaleData) {
+		return hydrateErr
+	}
+
+	if err := r.Render(ctx, *siteCfg); err != nil {
+		return err
+	}
+
+	return hydrateErr
 }
 
-func initConfigHydrators(out io.Writer, checksum string, modules []string) []site.Hydrator {
+func initModuleFinder(out io.Writer, cloner git.Cloner, opts options) (module.Finder, error) {
+	finderOpts := []git.ModuleFinderOption{git.WithCloner(cloner)}
+
+	if opts.skipInvalidModules {
+		finderOpts = append(finderOpts, git.WithInvalidGoModHandler(func(err *module.GoModError) {
+			_, _ = fmt.Fprintln(out, color.HiYellowString("Skip Module"), ":", err) //nolint: errcheck
+		}))
+	}
+
+	finder := git.NewModuleFinder(finderOpts...)
+
+	switch opts.strategy {
+	case strategyClone:
+		return finder, nil
+
+	case strategyRemote:
+		return git.NewRemoteModuleFinder(finder), nil
+
+	case strategyProxy:
+		return goproxy.NewFinder(goproxy.WithEnv(os.Getenv), goproxy.WithFallback(finder)), nil
+	}
+
+	return nil, fmt.Errorf("unknown strategy %q", opts.strategy) // nolint: err113
+}
+
+func initConfigHydrators(out io.Writer, cloner git.Cloner, finder module.Finder, checksum string, cfg config.Config, opts options) []site.Hydrator {
+	githubOpts := []github.HydratorOption{
+		github.WithCloneTimeout(opts.cloneTimeout),
+		github.WithOutput(out),
+	}
+
+	// The README files are only shown on the landing pages.
+	if cfg.LandingPages {
+		githubOpts = append(githubOpts, github.WithReadmes())
+	}
+
+	var upstream site.Hydrator = site.Hydrators{
+		vcs.NewHydrator(vcs.WithOutput(out)),
+		github.NewHydrator(finder, githubOpts...),
+	}
+
+	if opts.continueOnError {
+		upstream = sitefallback.NewHydrator(
+			sitecache.NewMetadataHydrator(checksum, sitecache.WithoutChecksumValidation(), sitecache.WithOutput(out)),
+			upstream,
+			sitefallback.WithOutput(out),
+		)
+	}
+
+	// The repositories are already cloned to find the modules, so reading their README and LICENSE files is free. It
+	// runs after the fallback, so the repositories that could be fetched are described even when others could not.
+	if opts.strategy == strategyClone {
+		upstream = siteinfo.NewHydrator(
+			upstream,
+			cloner,
+			siteinfo.WithCloneTimeout(opts.cloneTimeout),
+			siteinfo.WithOutput(out),
+		)
+	}
+
 	return []site.Hydrator{
 		sitefragment.NewHydrator(
 			sitecache.NewMetadataHydrator(checksum, sitecache.WithOutput(out)),
-			github.NewHydrator(git.NewModuleFinder(), github.WithOutput(out)),
-			modules,
+			upstream,
+			opts.modules,
 			sitefragment.WithOutput(out),
 		),
 	}
 }
 
-func initHomepageSrc(homepageTpl string) (string, error) {
-	if len(homepageTpl) > 0 {
-		data, err := os.ReadFile(filepath.Clean(homepageTpl))
+// siteTemplates are the templates of the site.
+type siteTemplates struct {
+	homepage   site.Template
+	notFound   site.Template
+	repository site.Template
+	// module is the template of the module landing pages, nil if the modules redirect to pkg.go.dev.
+	module   *site.Template
+	partials map[string]site.Template
+	assets   map[string][]byte
+}
+
+// initTemplates loads the templates. A template file set by the flags or the config is used first, then the file of the
+// same name in the templates directory, then the embedded template. The flags take precedence over the config. The
+// module template is only loaded when the landing pages are enabled.
+func initTemplates(cfg config.Templates, landingPages bool, opts options) (siteTemplates, error) {
+	dir := firstNonEmpty(opts.templatesDir, cfg.Dir)
+
+	if len(dir) > 0 {
+		fi, err := os.Stat(filepath.Clean(dir))
+		if err != nil {
+			return siteTemplates{}, fmt.Errorf("could not read templates directory: %w", err)
+		}
+
+		if !fi.IsDir() {
+			return siteTemplates{}, fmt.Errorf("templates directory %q is not a directory", dir) // nolint: err113
+		}
+	}
+
+	var (
+		tpls siteTemplates
+		err  error
+	)
+
+	if tpls.homepage, err = initTemplate(templates.HomepageFile, templates.EmbeddedHomepage(), dir, firstNonEmpty(opts.homepageTpl, cfg.Homepage)); err != nil {
+		return siteTemplates{}, fmt.Errorf("could not read homepage template: %w", err)
+	}
+
+	if tpls.notFound, err = initTemplate(templates.NotFoundFile, templates.EmbeddedNotFound(), dir, firstNonEmpty(opts.notFoundTpl, cfg.NotFound)); err != nil {
+		return siteTemplates{}, fmt.Errorf("could not read 404 template: %w", err)
+	}
+
+	if tpls.repository, err = initTemplate(templates.RepositoryFile, templates.EmbeddedRepository(), dir, firstNonEmpty(opts.repositoryTpl, cfg.Repository)); err != nil {
+		return siteTemplates{}, fmt.Errorf("could not read repository template: %w", err)
+	}
+
+	if landingPages {
+		tpl, err := initTemplate(templates.ModuleFile, templates.EmbeddedModule(), dir, firstNonEmpty(opts.moduleTpl, cfg.Module))
+		if err != nil {
+			return siteTemplates{}, fmt.Errorf("could not read module template: %w", err)
+		}
+
+		tpls.module = &tpl
+	}
+
+	if tpls.partials, err = initPartials(dir); err != nil {
+		return siteTemplates{}, fmt.Errorf("could not read partials: %w", err)
+	}
+
+	if tpls.assets, err = initAssets(dir); err != nil {
+		return siteTemplates{}, fmt.Errorf("could not read assets: %w", err)
+	}
+
+	return tpls, nil
+}
+
+func initTemplate(name, embedded, dir, file string) (site.Template, error) {
+	if len(file) == 0 && len(dir) > 0 {
+		_, err := os.Stat(filepath.Join(dir, name))
+
+		switch {
+		case err == nil:
+			file = filepath.Join(dir, name)
+
+		case !os.IsNotExist(err):
+			return site.Template{}, fmt.Errorf("could not read template %s: %w", name, err)
+		}
+	}
+
+	if len(file) == 0 {
+		return site.NewTemplate(name, embedded), nil
+	}
+
+	data, err := os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		return site.Template{}, err
+	}
+
+	return site.NewTemplate(file, string(data)), nil
+}
+
+// initPartials loads the embedded partials, then the partials in the templates directory, which replace the embedded
+// ones with the same names.
+func initPartials(dir string) (map[string]site.Template, error) {
+	partials := make(map[string]site.Template)
+
+	for name, src := range templates.EmbeddedPartials() {
+		partials[name] = site.NewTemplate(path.Join(templates.PartialsDir, name+templates.PartialExt), src)
+	}
+
+	if len(dir) == 0 {
+		return partials, nil
+	}
+
+	partialsDir := filepath.Join(dir, templates.PartialsDir)
+
+	if _, err := os.Stat(partialsDir); os.IsNotExist(err) {
+		return partials, nil
+	}
+
+	err := filepath.WalkDir(partialsDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(p) != templates.PartialExt {
+			return err
+		}
+
+		rel, err := filepath.Rel(partialsDir, p)
+		if err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(filepath.Clean(p))
+		if err != nil {
+			return err
+		}
+
+		partials[templates.PartialName(filepath.ToSlash(rel))] = site.NewTemplate(p, string(data))
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return partials, nil
+}
+
+// initAssets loads the embedded assets, then the files in the assets directory of the templates directory, which replace
+// the embedded ones with the same paths.
+func initAssets(dir string) (map[string][]byte, error) {
+	assets := templates.EmbeddedAssets()
+
+	if len(dir) == 0 {
+		return assets, nil
+	}
+
+	assetsDir := filepath.Join(dir, templates.AssetsDir)
+
+	if _, err := os.Stat(assetsDir); os.IsNotExist(err) {
+		return assets, nil
+	}
+
+	err := filepath.WalkDir(assetsDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(assetsDir, p)
+		if err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(filepath.Clean(p))
 		if err != nil {
-			return "", fmt.Errorf("could not read homepage template: %w", err)
+			return err
 		}
 
-		return string(data), nil
+		assets[filepath.ToSlash(rel)] = data
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return templates.EmbeddedHomepage(), nil
+	return assets, nil
 }
 
 func initOutputDir(outputPath string) (string, error) {
@@ -130,52 +439,116 @@ func initOutputDir(outputPath string) (string, error) {
 	return outputPath, nil
 }
 
-func initSiteConfig(out io.Writer, configFile, checksum string, modules []string) (*site.Site, error) {
-	cfg, err := config.FromFile(configFile)
-	if err != nil {
-		return nil, err
-	}
-
+func initSiteConfig(ctx context.Context, out io.Writer, cloner git.Cloner, finder module.Finder, checksum string, cfg config.Config, opts options) (*site.Site, error) {
 	s := site.Site{
 		PageTitle:       cfg.PageTitle,
 		PageDescription: cfg.PageDescription,
 		Hostname:        cfg.Host,
 		SourceURL:       cfg.SourceURL,
 		Repositories:    make([]site.Repository, len(cfg.Repositories)),
+		Categories:      cfg.Categories,
+		Search:          cfg.Search,
+		DocsURLTemplate: cfg.DocsURLTemplate,
+		DisableRedirect: cfg.DisableRedirect,
+		SelfContained:   cfg.SelfContained,
+		NoInlineScripts: cfg.NoInlineScripts,
+		Params:          cfg.Params,
 	}
 
 	for i, r := range cfg.Repositories {
 		s.Repositories[i] = site.Repository{
-			Name:          r.Name,
-			Path:          r.Path,
-			Deprecated:    r.Deprecated,
-			Hidden:        r.Hidden,
-			RepositoryURL: r.Repository,
-			Ref:           r.Ref,
+			Name:            r.Name,
+			Path:            r.Path,
+			Deprecated:      r.Deprecated,
+			Hidden:          r.Hidden,
+			VCS:             r.VCS,
+			RepositoryURL:   r.Repository,
+			Ref:             r.Ref,
+			CloneTimeout:    time.Duration(r.CloneTimeout),
+			Submodules:      r.Submodules,
+			AllTags:         r.AllTags,
+			Include:         r.Include,
+			Exclude:         r.Exclude,
+			Description:     r.Description,
+			Tags:            r.Tags,
+			Category:        r.Category,
+			Homepage:        r.Homepage,
+			DocsURL:         r.DocsURL,
+			DocsURLTemplate: r.DocsURLTemplate,
+			License:         r.License,
+			Params:          r.Params,
 		}
 	}
 
-	err = site.Hydrate(&s, initConfigHydrators(out, checksum, modules)...)
-	if err != nil {
+	err := site.Hydrate(ctx, &s, initConfigHydrators(out, cloner, finder, checksum, cfg, opts)...)
+	if err != nil && !errors.Is(err, site.ErrStaleData) {
 		return nil, err
 	}
 
-	return &s, nil
+	return &s, err
 }
 
-func initRenderer(out io.Writer, homepageSrc, outputPath, checksum string) (site.Renderder, error) {
+func initRenderer(out io.Writer, cloner git.Cloner, tpls siteTemplates, outputPath, checksum string, opts options) (site.Renderder, error) {
 	var r site.Renderder
 
-	r, err := site.NewHandlebarsRenderder(homepageSrc, templates.EmbeddedNotFound(), templates.EmbeddedRepository(), outputPath, site.WithOutput(out))
+	rendererOpts := []site.RendererOption{
+		site.WithPartials(tpls.partials),
+		site.WithAssets(tpls.assets),
+		site.WithOutput(out),
+	}
+
+	if tpls.module != nil {
+		rendererOpts = append(rendererOpts, site.WithModuleTemplate(*tpls.module))
+	}
+
+	r, err := site.NewHandlebarsRenderder(tpls.homepage, tpls.notFound, tpls.repository, outputPath, rendererOpts...)
 	if err != nil {
 		return nil, err
 	}
 
 	r = sitecache.NewRenderder(r, outputPath, checksum, sitecache.WithOutput(out))
 
+	if len(opts.modProxyDir) > 0 {
+		proxyOpts := []siteproxy.RendererOption{
+			siteproxy.WithCloneTimeout(opts.cloneTimeout),
+			siteproxy.WithOutput(out),
+		}
+
+		if len(opts.modProxyURL) > 0 {
+			proxyOpts = append(proxyOpts, siteproxy.WithProxyURL(opts.modProxyURL))
+		}
+
+		// The cached cloner reuses the clones of the hydration.
+		r = siteproxy.NewRenderder(r, cloner, outputPath, opts.modProxyDir, proxyOpts...)
+	}
+
 	return r, nil
 }
 
+func printError(w io.Writer, err error) {
+	failures := site.RepositoryErrors(err)
+	if len(failures) == 0 {
+		_, _ = fmt.Fprintf(w, "%s\n", err) //nolint: errcheck
+
+		return
+	}
+
+	if errors.Is(err, site.ErrStaleData) {
+		_, _ = fmt.Fprintf(w, "%d repositories could not be fetched, their previously published data is used:\n", len(failures)) //nolint: errcheck
+	} else {
+		_, _ = fmt.Fprintf(w, "%d repositories could not be fetched:\n", len(failures)) //nolint: errcheck
+	}
+
+	for _, f := range failures {
+		name := f.Path
+		if len(f.Name) > 0 {
+			name = fmt.Sprintf("%s (%s)", f.Path, f.Name)
+		}
+
+		_, _ = fmt.Fprintf(w, "  - %s: %s\n", name, f.Err) //nolint: errcheck
+	}
+}
+
 func checksum(path string) (string, error) {
 	f, err := os.Open(filepath.Clean(path))
 	if err != nil {
@@ -194,6 +567,16 @@ func checksum(path string) (string, error) {
 	return hex.EncodeToString(h.Sum(nil)), nil
 }
 
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if len(v) > 0 {
+			return v
+		}
+	}
+
+	return ""
+}
+
 func split(s, sep string) []string {
 	var r []string
 
@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 
 	xerrors "go.nhat.io/vanityrender/internal/errors"
+	"go.nhat.io/vanityrender/internal/module"
+	"go.nhat.io/vanityrender/internal/vcs"
 )
 
 const (
@@ -25,6 +27,38 @@ type Config struct {
 	Host            string       `json:"host"`
 	SourceURL       string       `json:"source_url"`
 	Repositories    []Repository `json:"repositories"`
+
+	// Templates overrides the embedded templates.
+	Templates Templates `json:"templates"`
+	// Categories are the order of the categories of the repositories on the homepage.
+	Categories []string `json:"categories"`
+	// Search shows a box to filter the repositories on the homepage.
+	Search bool `json:"search"`
+	// LandingPages renders a landing page for each module instead of redirecting to pkg.go.dev.
+	LandingPages bool `json:"landing_pages"`
+	// DocsURLTemplate is the URL of the documentation of a module, with the {module}, {path} and {version} placeholders,
+	// e.g. https://pkgsite.example.com/{module}. The default is https://pkg.go.dev/{module}.
+	DocsURLTemplate string `json:"docs_url_template"`
+	// DisableRedirect keeps the visitors on the module pages instead of redirecting them to the documentation.
+	DisableRedirect bool `json:"disable_redirect"`
+	// SelfContained serves the stylesheets, the scripts and the images from the output directory instead of the CDNs.
+	SelfContained bool `json:"self_contained"`
+	// NoInlineScripts loads the scripts of the pages from a file, for a Content-Security-Policy without 'unsafe-inline'.
+	NoInlineScripts bool `json:"no_inline_scripts"`
+	// Params are free-form variables passed to all the templates, e.g. a logo URL or an analytics ID.
+	Params map[string]any `json:"params"`
+}
+
+// Templates is the configuration for the templates. The paths are relative to the config file.
+type Templates struct {
+	// Dir is a directory that overrides the embedded templates with the files of the same names, e.g.
+	// repository.html.hbs.
+	Dir        string `json:"dir"`
+	Homepage   string `json:"homepage"`
+	NotFound   string `json:"notfound"`
+	Repository string `json:"repository"`
+	// Module is the template of the module landing pages, used when the landing pages are enabled.
+	Module string `json:"module"`
 }
 
 // Repository is the configuration for a repository.
@@ -35,6 +69,37 @@ type Repository struct {
 	Ref        string `json:"ref"`
 	Deprecated string `json:"deprecated"`
 	Hidden     bool   `json:"hidden"`
+	// VCS is the version control system of the repository: git (default), hg, svn, bzr, fossil, or mod when the
+	// repository is the URL of a Go module proxy.
+	VCS string `json:"vcs"`
+
+	// CloneTimeout overrides the default timeout for fetching the repository.
+	CloneTimeout Duration `json:"clone_timeout"`
+	// Submodules are the paths of the submodules relative to the repository path, used when the modules could not be
+	// discovered from the repository itself, e.g. with a module proxy.
+	Submodules []string `json:"submodules"`
+	// AllTags includes the tags that are not reachable from the ref, e.g. the tags of the release branches.
+	AllTags bool `json:"all_tags"`
+	// Include selects only the submodules whose directories match one of the glob patterns.
+	Include []string `json:"include"`
+	// Exclude ignores the submodules whose directories match one of the glob patterns.
+	Exclude []string `json:"exclude"`
+	// Description is a short description of the repository, the first paragraph of its README file if empty.
+	Description string `json:"description"`
+	// Tags are the labels of the repository, e.g. ["testing", "mock"].
+	Tags []string `json:"tags"`
+	// Category is the section of the homepage where the repository is listed.
+	Category string `json:"category"`
+	// Homepage is the URL of the website of the project.
+	Homepage string `json:"homepage"`
+	// DocsURL is the URL of the documentation.
+	DocsURL string `json:"docs_url"`
+	// DocsURLTemplate overrides the documentation URL template of the config for the modules of the repository.
+	DocsURLTemplate string `json:"docs_url_template"`
+	// License is the SPDX identifier of the license, e.g. MIT, detected from the LICENSE file if empty.
+	License string `json:"license"`
+	// Params are free-form variables passed to the templates of the repository, over the ones of the site.
+	Params map[string]any `json:"params"`
 }
 
 // FromFile reads the configuration from a file.
@@ -60,7 +125,7 @@ func FromFile(file string) (Config, error) {
 		return Config{}, err
 	}
 
-	hydrateConfig(&cfg)
+	hydrateConfig(&cfg, filepath.Dir(file))
 
 	return cfg, nil
 }
@@ -71,11 +136,29 @@ func Validate(config Config) error {
 		return ErrMissingHost
 	}
 
+	for _, r := range config.Repositories {
+		if !vcs.IsValid(r.VCS) {
+			return fmt.Errorf("%w: repository %q: unsupported vcs %q", ErrInvalidConfig, r.Path, r.VCS)
+		}
+
+		filter := module.PathFilter{Include: r.Include, Exclude: r.Exclude}
+
+		if err := filter.Validate(); err != nil {
+			return fmt.Errorf("%w: repository %q: %w", ErrInvalidConfig, r.Path, err)
+		}
+	}
+
 	return nil
 }
 
-func hydrateConfig(cfg *Config) {
+func hydrateConfig(cfg *Config, dir string) {
 	if len(cfg.PageTitle) == 0 {
 		cfg.PageTitle = cfg.Host
 	}
+
+	for _, p := range []*string{&cfg.Templates.Dir, &cfg.Templates.Homepage, &cfg.Templates.NotFound, &cfg.Templates.Repository, &cfg.Templates.Module} {
+		if len(*p) > 0 && !filepath.IsAbs(*p) {
+			*p = filepath.Join(dir, *p)
+		}
+	}
 }
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -24,12 +25,71 @@ func TestFromFile(t *testing.T) {
         {
             "name": "Vanity Renderder",
             "path": "vanityrender",
-            "repository": "https://github.com/nhatthm/govanityrender"
+            "repository": "https://github.com/nhatthm/govanityrender",
+            "clone_timeout": "30s",
+            "exclude": ["examples"],
+            "description": "Render the vanity pages",
+            "tags": ["tool"],
+            "category": "Tools",
+            "homepage": "https://go.nhat.io",
+            "docs_url": "https://docs.go.nhat.io/vanityrender",
+            "docs_url_template": "https://godoc.example.com/{path}",
+            "license": "MIT",
+            "params": {
+                "badge": "stable"
+            }
+        }
+    ],
+    "templates": {
+        "dir": "templates",
+        "homepage": "/etc/vanityrender/homepage.html.hbs",
+        "module": "module.html.hbs"
+    },
+    "categories": ["Tools", "Testing"],
+    "search": true,
+    "landing_pages": true,
+    "docs_url_template": "https://pkgsite.example.com/{module}",
+    "disable_redirect": true,
+    "self_contained": true,
+    "no_inline_scripts": true,
+    "params": {
+        "logoURL": "https://go.nhat.io/logo.svg",
+        "analytics": {"id": "G-123", "enabled": true}
+    }
+}`
+		payloadInvalidTimeout = `{
+    "host": "go.nhat.io",
+    "repositories": [
+        {
+            "name": "Vanity Renderder",
+            "clone_timeout": "30 seconds"
+        }
+    ]
+}`
+		payloadInvalidVCS = `{
+    "host": "go.nhat.io",
+    "repositories": [
+        {
+            "name": "Vanity Renderder",
+            "path": "vanityrender",
+            "vcs": "cvs"
+        }
+    ]
+}`
+		payloadInvalidPattern = `{
+    "host": "go.nhat.io",
+    "repositories": [
+        {
+            "name": "Vanity Renderder",
+            "path": "vanityrender",
+            "exclude": ["examples/["]
         }
     ]
 }`
 	)
 
+	successFile := testFile(t, "success.json", payloadOK)
+
 	testCases := []struct {
 		scenario             string
 		file                 string
@@ -55,19 +115,63 @@ func TestFromFile(t *testing.T) {
 			expectedError:        config.ErrMissingHost,
 			expectedErrorMessage: "missing host",
 		},
+		{
+			scenario:             "invalid clone timeout",
+			file:                 testFile(t, "invalid_timeout.json", payloadInvalidTimeout),
+			expectedError:        config.ErrInvalidConfig,
+			expectedErrorMessage: `invalid config: time: unknown unit " seconds" in duration "30 seconds"`,
+		},
+		{
+			scenario:             "invalid vcs",
+			file:                 testFile(t, "invalid_vcs.json", payloadInvalidVCS),
+			expectedError:        config.ErrInvalidConfig,
+			expectedErrorMessage: `invalid config: repository "vanityrender": unsupported vcs "cvs"`,
+		},
+		{
+			scenario:             "invalid pattern",
+			file:                 testFile(t, "invalid_pattern.json", payloadInvalidPattern),
+			expectedError:        config.ErrInvalidConfig,
+			expectedErrorMessage: `invalid config: repository "vanityrender": invalid pattern "examples/[": syntax error in pattern`,
+		},
 		{
 			scenario: "success",
-			file:     testFile(t, "success.json", payloadOK),
+			file:     successFile,
 			expectedResult: config.Config{
 				PageTitle: "go.nhat.io",
 				Host:      "go.nhat.io",
 				Repositories: []config.Repository{
 					{
-						Name:       "Vanity Renderder",
-						Path:       "vanityrender",
-						Repository: "https://github.com/nhatthm/govanityrender",
+						Name:            "Vanity Renderder",
+						Path:            "vanityrender",
+						Repository:      "https://github.com/nhatthm/govanityrender",
+						CloneTimeout:    config.Duration(30 * time.Second),
+						Exclude:         []string{"examples"},
+						Description:     "Render the vanity pages",
+						Tags:            []string{"tool"},
+						Category:        "Tools",
+						Homepage:        "https://go.nhat.io",
+						DocsURL:         "https://docs.go.nhat.io/vanityrender",
+						DocsURLTemplate: "https://godoc.example.com/{path}",
+						License:         "MIT",
+						Params:          map[string]any{"badge": "stable"},
 					},
 				},
+				Templates: config.Templates{
+					Dir:      filepath.Join(filepath.Dir(successFile), "templates"),
+					Homepage: "/etc/vanityrender/homepage.html.hbs",
+					Module:   filepath.Join(filepath.Dir(successFile), "module.html.hbs"),
+				},
+				Categories:      []string{"Tools", "Testing"},
+				Search:          true,
+				LandingPages:    true,
+				DocsURLTemplate: "https://pkgsite.example.com/{module}",
+				DisableRedirect: true,
+				SelfContained:   true,
+				NoInlineScripts: true,
+				Params: map[string]any{
+					"logoURL":   "https://go.nhat.io/logo.svg",
+					"analytics": map[string]any{"id": "G-123", "enabled": true},
+				},
 			},
 		},
 	}
@@ -0,0 +1,66 @@
+package config_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/vanityrender/internal/config"
+)
+
+func TestDuration_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	actual, err := json.Marshal(config.Duration(90 * time.Second))
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `"1m30s"`, string(actual))
+}
+
+func TestDuration_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		data          string
+		expected      config.Duration
+		expectedError string
+	}{
+		{
+			scenario:      "not a string",
+			data:          `30`,
+			expectedError: "duration must be a string: json: cannot unmarshal number into Go value of type string",
+		},
+		{
+			scenario:      "invalid duration",
+			data:          `"30"`,
+			expectedError: `time: missing unit in duration "30"`,
+		},
+		{
+			scenario: "success",
+			data:     `"2m"`,
+			expected: config.Duration(2 * time.Minute),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			var actual config.Duration
+
+			err := json.Unmarshal([]byte(tc.data), &actual)
+
+			assert.Equal(t, tc.expected, actual)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
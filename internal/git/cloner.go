@@ -0,0 +1,151 @@
+package git
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// Cloner clones repositories.
+type Cloner interface {
+	Clone(ctx context.Context, url, ref string) (string, *git.Repository, error)
+}
+
+// ClonerFunc is a function that implements Cloner.
+type ClonerFunc func(ctx context.Context, url, ref string) (string, *git.Repository, error)
+
+// Clone clones a repository.
+func (f ClonerFunc) Clone(ctx context.Context, url, ref string) (string, *git.Repository, error) {
+	return f(ctx, url, ref)
+}
+
+var _ Cloner = (*CachedCloner)(nil)
+
+type cloneRequest struct {
+	Repository string
+	Ref        string
+}
+
+type cloneCall struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+
+	// waiters is the number of callers waiting for the clone, guarded by the mutex of the cloner.
+	waiters int
+
+	dir  string
+	repo *git.Repository
+	err  error
+}
+
+// CachedCloner clones a repository at a ref only once and shares the result with all the callers. Concurrent calls for
+// the same repository and ref wait for the first one to finish, while calls for different ones run in parallel.
+//
+// The clone is not canceled when one of the callers gives up, only when all of them do. The failures are not cached, the
+// next call clones again.
+type CachedCloner struct {
+	upstream Cloner
+
+	mu    sync.Mutex
+	calls map[cloneRequest]*cloneCall
+}
+
+// Clone clones a repository.
+func (c *CachedCloner) Clone(ctx context.Context, url, ref string) (string, *git.Repository, error) {
+	req := cloneRequest{
+		Repository: url,
+		Ref:        ref,
+	}
+
+	call := c.call(ctx, req)
+
+	select {
+	case <-ctx.Done():
+		c.leave(req, call)
+
+		return "", nil, ctx.Err()
+
+	case <-call.done:
+		return call.dir, call.repo, call.err
+	}
+}
+
+// call returns the clone of the request, a new one is started if there is none.
+func (c *CachedCloner) call(ctx context.Context, req cloneRequest) *cloneCall {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if call, ok := c.calls[req]; ok {
+		call.waiters++
+
+		return call
+	}
+
+	// The clone runs on behalf of all the callers, so it does not stop when the first one is canceled.
+	cloneCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+	call := &cloneCall{
+		done:    make(chan struct{}),
+		cancel:  cancel,
+		waiters: 1,
+	}
+
+	c.calls[req] = call
+
+	go c.clone(cloneCtx, req, call)
+
+	return call
+}
+
+func (c *CachedCloner) clone(ctx context.Context, req cloneRequest, call *cloneCall) {
+	defer call.cancel()
+
+	dir, repo, err := c.upstream.Clone(ctx, req.Repository, req.Ref)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	call.dir, call.repo, call.err = dir, repo, err
+
+	if err != nil {
+		c.forget(req, call)
+	}
+
+	close(call.done)
+}
+
+// leave removes a caller from the waiters of a clone, the clone is canceled when nobody waits for it anymore.
+func (c *CachedCloner) leave(req cloneRequest, call *cloneCall) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	call.waiters--
+
+	select {
+	case <-call.done:
+		return
+
+	default:
+	}
+
+	if call.waiters == 0 {
+		call.cancel()
+		c.forget(req, call)
+	}
+}
+
+// forget removes a clone from the cache, unless it has been replaced already. The mutex must be held.
+func (c *CachedCloner) forget(req cloneRequest, call *cloneCall) {
+	if c.calls[req] == call {
+		delete(c.calls, req)
+	}
+}
+
+// NewCachedCloner returns a new cached cloner.
+func NewCachedCloner(upstream Cloner) *CachedCloner {
+	return &CachedCloner{
+		upstream: upstream,
+		calls:    make(map[cloneRequest]*cloneCall),
+	}
+}
@@ -0,0 +1,229 @@
+package git_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/vanityrender/internal/git"
+)
+
+func TestCachedCloner_Clone_Error(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	c := git.NewCachedCloner(git.ClonerFunc(func(context.Context, string, string) (string, *gogit.Repository, error) {
+		calls.Add(1)
+
+		return "", nil, errors.New("clone error")
+	}))
+
+	for range 3 {
+		_, _, err := c.Clone(t.Context(), "https://github.com/org/repository", "")
+
+		require.EqualError(t, err, "clone error")
+	}
+
+	// The failures are not cached.
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestCachedCloner_Clone_Deduplicate(t *testing.T) {
+	t.Parallel()
+
+	var calls sync.Map
+
+	c := git.NewCachedCloner(git.ClonerFunc(func(_ context.Context, url, ref string) (string, *gogit.Repository, error) {
+		n, _ := calls.LoadOrStore(url+"@"+ref, new(atomic.Int32))
+		n.(*atomic.Int32).Add(1) // nolint: forcetypeassert
+
+		return url + "@" + ref, nil, nil
+	}))
+
+	wg := sync.WaitGroup{}
+
+	for range 10 {
+		for _, ref := range []string{"", "master", "v1.0.0"} {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				dir, _, err := c.Clone(t.Context(), "https://github.com/org/repository", ref)
+
+				assert.NoError(t, err)
+				assert.Equal(t, "https://github.com/org/repository@"+ref, dir)
+			}()
+		}
+	}
+
+	wg.Wait()
+
+	calls.Range(func(key, value any) bool {
+		assert.Equalf(t, int32(1), value.(*atomic.Int32).Load(), "%s is cloned more than once", key) // nolint: forcetypeassert
+
+		return true
+	})
+}
+
+func TestCachedCloner_Clone_DoesNotBlockOtherRepositories(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+
+	c := git.NewCachedCloner(git.ClonerFunc(func(_ context.Context, url, _ string) (string, *gogit.Repository, error) {
+		if url == "slow" {
+			<-release
+		}
+
+		return url, nil, nil
+	}))
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		_, _, _ = c.Clone(t.Context(), "slow", "") // nolint: errcheck
+	}()
+
+	dir, _, err := c.Clone(t.Context(), "fast", "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "fast", dir)
+
+	close(release)
+	<-done
+}
+
+func TestCachedCloner_Clone_Canceled(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	c := git.NewCachedCloner(git.ClonerFunc(func(context.Context, string, string) (string, *gogit.Repository, error) {
+		close(started)
+		<-release
+
+		return "", nil, nil
+	}))
+
+	go func() {
+		_, _, _ = c.Clone(context.Background(), "slow", "") // nolint: errcheck
+	}()
+
+	<-started
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	_, _, err := c.Clone(ctx, "slow", "")
+
+	assert.ErrorIs(t, err, context.Canceled)
+
+	close(release)
+}
+
+func TestCachedCloner_Clone_FirstCallerCanceled(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	c := git.NewCachedCloner(git.ClonerFunc(func(ctx context.Context, url, _ string) (string, *gogit.Repository, error) {
+		calls.Add(1)
+		close(started)
+
+		select {
+		case <-ctx.Done():
+			return "", nil, ctx.Err()
+
+		case <-release:
+			return url, nil, nil
+		}
+	}))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	result := make(chan error)
+
+	go func() {
+		_, _, err := c.Clone(ctx, "slow", "")
+
+		result <- err
+	}()
+
+	<-started
+
+	done := make(chan string)
+
+	go func() {
+		dir, _, err := c.Clone(t.Context(), "slow", "")
+		assert.NoError(t, err)
+
+		done <- dir
+	}()
+
+	// Let the other caller join the clone.
+	time.Sleep(50 * time.Millisecond)
+
+	// The other caller keeps waiting for the clone when the first one is canceled.
+	cancel()
+
+	require.ErrorIs(t, <-result, context.Canceled)
+
+	close(release)
+
+	assert.Equal(t, "slow", <-done)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestCachedCloner_Clone_AllCallersCanceled(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	c := git.NewCachedCloner(git.ClonerFunc(func(ctx context.Context, url, _ string) (string, *gogit.Repository, error) {
+		if calls.Add(1) > 1 {
+			return url, nil, nil
+		}
+
+		<-ctx.Done()
+
+		return "", nil, ctx.Err()
+	}))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+
+	_, _, err := c.Clone(ctx, "repository", "")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// The canceled clone is not cached, the next caller clones again.
+	require.Eventually(t, func() bool {
+		dir, _, err := c.Clone(t.Context(), "repository", "")
+
+		return err == nil && dir == "repository"
+	}, time.Second, 10*time.Millisecond)
+}
+
+// fakeCloner clones the local repositories instead of the remote ones.
+func fakeCloner(repositories map[string]string) git.Cloner {
+	return git.ClonerFunc(func(ctx context.Context, url, ref string) (string, *gogit.Repository, error) {
+		if loc, ok := repositories[url]; ok {
+			url = loc
+		}
+
+		return git.Clone(ctx, url, ref)
+	})
+}
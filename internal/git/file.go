@@ -0,0 +1,62 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// ReadHeadFile reads the file of the directory at HEAD that has the lowest non-negative rank, e.g. README.md before
+// README. It returns an empty name if no file is ranked.
+func ReadHeadFile(r *git.Repository, dir string, rank func(name string) int) (string, []byte, error) {
+	head, err := r.Head()
+	if err != nil {
+		return "", nil, fmt.Errorf("could not get head: %w", err)
+	}
+
+	c, err := r.CommitObject(head.Hash())
+	if err != nil {
+		return "", nil, fmt.Errorf("could not get head commit: %w", err)
+	}
+
+	tree, err := c.Tree()
+	if err != nil {
+		return "", nil, fmt.Errorf("could not get tree: %w", err)
+	}
+
+	if dir != "." && dir != "" {
+		if tree, err = tree.Tree(dir); err != nil {
+			if errors.Is(err, object.ErrDirectoryNotFound) {
+				return "", nil, nil
+			}
+
+			return "", nil, fmt.Errorf("could not get tree %q: %w", dir, err)
+		}
+	}
+
+	name, best := "", -1
+
+	for _, e := range tree.Entries {
+		if !e.Mode.IsFile() {
+			continue
+		}
+
+		if n := rank(e.Name); n >= 0 && (best < 0 || n < best) {
+			name, best = e.Name, n
+		}
+	}
+
+	if best < 0 {
+		return "", nil, nil
+	}
+
+	data, err := readFile(c, path.Join(dir, name))
+	if err != nil {
+		return "", nil, err
+	}
+
+	return name, data, nil
+}
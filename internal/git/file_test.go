@@ -0,0 +1,81 @@
+package git_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/vanityrender/internal/git"
+)
+
+func TestReadHeadFile(t *testing.T) {
+	t.Parallel()
+
+	dir := mockRepository(func(t *testing.T, r *gogit.Repository, dir string) {
+		t.Helper()
+
+		writeFile(t, filepath.Join(dir, "README"), "plain\n")
+		writeFile(t, filepath.Join(dir, "README.md"), "markdown\n")
+		writeFile(t, filepath.Join(dir, "contrib", "readme.md"), "contrib\n")
+		writeFile(t, filepath.Join(dir, "docs", "README.md", "index.md"), "not a file\n")
+		commitAndPush(t, r, "Add readme")
+	})(t)
+
+	r, err := gogit.PlainOpen(dir)
+	require.NoError(t, err)
+
+	rank := func(name string) int {
+		switch strings.ToLower(name) {
+		case "readme.md":
+			return 0
+		case "readme":
+			return 1
+		}
+
+		return -1
+	}
+
+	testCases := []struct {
+		scenario     string
+		dir          string
+		expectedName string
+		expectedData string
+	}{
+		{
+			scenario:     "root",
+			dir:          ".",
+			expectedName: "README.md",
+			expectedData: "markdown\n",
+		},
+		{
+			scenario:     "subdirectory",
+			dir:          "contrib",
+			expectedName: "readme.md",
+			expectedData: "contrib\n",
+		},
+		{
+			scenario: "directory is not a file",
+			dir:      "docs",
+		},
+		{
+			scenario: "directory not found",
+			dir:      "unknown",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			name, data, err := git.ReadHeadFile(r, tc.dir, rank)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expectedName, name)
+			assert.Equal(t, tc.expectedData, string(data))
+		})
+	}
+}
@@ -1,64 +1,39 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"sort"
-	"sync"
+	"strings"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"golang.org/x/mod/modfile"
+	xmodule "golang.org/x/mod/module"
 
 	"go.nhat.io/vanityrender/internal/module"
 	"go.nhat.io/vanityrender/internal/must"
 )
 
-var (
-	cloneOnce = make(map[cloneRequest]*sync.Once)
-	cloned    = make(map[cloneRequest]func() (string, *git.Repository, error))
-	cloneMu   = sync.Mutex{}
-)
-
-type cloneRequest struct {
-	Repository string
-	Ref        string
-}
-
-// Clone clones a repository.
-func Clone(url string, ref string) (string, *git.Repository, error) {
-	cloneMu.Lock()
-	defer cloneMu.Unlock()
-
-	req := cloneRequest{
-		Repository: url,
-		Ref:        ref,
-	}
-
-	if _, ok := cloneOnce[req]; !ok {
-		cloneOnce[req] = new(sync.Once)
-	}
-
-	cloneOnce[req].Do(func() {
-		dir, r, err := clone(url, ref)
-
-		cloned[req] = func() (string, *git.Repository, error) {
-			return dir, r, err
-		}
-	})
-
-	return cloned[req]()
-}
+const goMod = `go.mod`
 
-func clone(url string, ref string) (string, *git.Repository, error) {
+// Clone clones a repository into a temporary directory and checks out the ref if it is not empty.
+func Clone(ctx context.Context, url string, ref string) (string, *git.Repository, error) {
 	dir, err := os.MkdirTemp("", "")
 	must.NoError(err)
 
-	r, err := git.PlainClone(dir, false, &git.CloneOptions{
+	r, err := git.PlainCloneContext(ctx, dir, false, &git.CloneOptions{
 		URL:      url,
 		Progress: io.Discard,
 	})
 	if err != nil {
+		_ = os.RemoveAll(dir) // nolint: errcheck
+
 		return "", nil, fmt.Errorf("could not clone repository: %w", err)
 	}
 
@@ -68,6 +43,8 @@ func clone(url string, ref string) (string, *git.Repository, error) {
 
 	commit, err := r.ResolveRevision(plumbing.Revision(ref))
 	if err != nil {
+		_ = os.RemoveAll(dir) // nolint: errcheck
+
 		return "", nil, fmt.Errorf("could not resolve ref %q: %w", ref, err)
 	}
 
@@ -78,24 +55,43 @@ func clone(url string, ref string) (string, *git.Repository, error) {
 		Hash: *commit,
 	})
 	if err != nil {
+		_ = os.RemoveAll(dir) // nolint: errcheck
+
 		return "", nil, fmt.Errorf("could not checkout revision %s: %w", commit.String(), err)
 	}
 
 	return dir, r, nil
 }
 
-// Versions returns all the versions up to HEAD in the repository.
+// Versions returns the versions that are tagged on HEAD or its ancestors, regardless of the commit times. The go.mod file
+// at the tagged commit is checked, see isModuleVersion.
 func Versions(r *git.Repository) ([]string, error) {
 	h, err := r.Head()
 	if err != nil {
 		return nil, fmt.Errorf("could not get head: %w", err)
 	}
 
-	headC, err := r.CommitObject(h.Hash())
+	reachable, err := ancestors(r, h.Hash())
 	if err != nil {
-		return nil, fmt.Errorf("could not get head commit: %w", err)
+		return nil, err
 	}
 
+	return versions(r, func(c *object.Commit) bool {
+		_, ok := reachable[c.Hash]
+
+		return ok
+	})
+}
+
+// AllVersions returns the versions of all the tags in the repository, including the tags that are not reachable from
+// HEAD, e.g. the tags of the release branches. The tags are checked like Versions does.
+func AllVersions(r *git.Repository) ([]string, error) {
+	return versions(r, func(*object.Commit) bool {
+		return true
+	})
+}
+
+func versions(r *git.Repository, include func(c *object.Commit) bool) ([]string, error) {
 	tags, err := r.Tags()
 	if err != nil {
 		return nil, fmt.Errorf("could not list tags: %w", err)
@@ -104,22 +100,26 @@ func Versions(r *git.Repository) ([]string, error) {
 	var tagNames []string
 
 	err = tags.ForEach(func(t *plumbing.Reference) error {
-		hash, err := r.ResolveRevision(plumbing.Revision(t.Name()))
-		if err != nil {
-			return fmt.Errorf("could not resolve tag %q: %w", t.Name(), err)
+		version := t.Name().Short()
+		if !module.PathVersionRegExp.MatchString(version) {
+			return nil
 		}
 
-		tagC, err := r.CommitObject(*hash)
+		tagC, err := tagCommit(r, t)
 		if err != nil {
-			return fmt.Errorf("could not get tag commit %q: %w", t.Name(), err)
+			return fmt.Errorf("could not get tag commit %q: %w", version, err)
 		}
 
-		// Ignore new tags.
-		if tagC.Committer.When.After(headC.Committer.When) {
+		if tagC == nil || !include(tagC) {
 			return nil
 		}
 
-		if version := t.Name().Short(); module.PathVersionRegExp.MatchString(version) {
+		ok, err := isModuleVersion(tagC, version)
+		if err != nil {
+			return fmt.Errorf("could not check tag %q: %w", version, err)
+		}
+
+		if ok {
 			tagNames = append(tagNames, version)
 		}
 
@@ -133,3 +133,159 @@ func Versions(r *git.Repository) ([]string, error) {
 
 	return tagNames, nil
 }
+
+// ancestors returns the commit and all its ancestors.
+func ancestors(r *git.Repository, hash plumbing.Hash) (map[plumbing.Hash]struct{}, error) {
+	commits, err := r.Log(&git.LogOptions{From: hash})
+	if err != nil {
+		return nil, fmt.Errorf("could not get history: %w", err)
+	}
+
+	result := make(map[plumbing.Hash]struct{})
+
+	err = commits.ForEach(func(c *object.Commit) error {
+		result[c.Hash] = struct{}{}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not walk history: %w", err)
+	}
+
+	return result, nil
+}
+
+// tagCommit returns the commit of a lightweight or an annotated tag. It returns nil if the tag does not point to a
+// commit.
+func tagCommit(r *git.Repository, t *plumbing.Reference) (*object.Commit, error) {
+	hash := t.Hash()
+
+	for {
+		tag, err := r.TagObject(hash)
+		if errors.Is(err, plumbing.ErrObjectNotFound) {
+			// Lightweight tag.
+			return r.CommitObject(hash) // nolint: wrapcheck
+		}
+
+		if err != nil {
+			return nil, err // nolint: wrapcheck
+		}
+
+		switch tag.TargetType {
+		case plumbing.CommitObject:
+			return tag.Commit() // nolint: wrapcheck
+
+		case plumbing.TagObject:
+			hash = tag.Target
+
+		default:
+			return nil, nil // nolint: nilnil
+		}
+	}
+}
+
+// isModuleVersion checks the go.mod file of the module at the tagged commit, see moduleDir. Unlike the go command, it
+// only rejects the tags that the go.mod file contradicts, i.e. the go.mod file declares another major version or could
+// not be read. The tags without go.mod file and the tags from v2 of a module without the major suffix are kept, like
+// they always were.
+func isModuleVersion(c *object.Commit, pathVersion string) (bool, error) {
+	_, ok, err := moduleDir(c, pathVersion)
+	if ok || err != nil {
+		return ok, err
+	}
+
+	dir := "."
+	if i := strings.LastIndex(pathVersion, "/"); i >= 0 {
+		dir = pathVersion[:i]
+	}
+
+	f, err := c.File(path.Join(dir, goMod))
+	if errors.Is(err, object.ErrFileNotFound) {
+		return true, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("could not get %s: %w", goMod, err)
+	}
+
+	data, err := f.Contents()
+	if err != nil {
+		return false, fmt.Errorf("could not read %s: %w", f.Name, err)
+	}
+
+	_, pathMajor, ok := xmodule.SplitPathVersion(modfile.ModulePath([]byte(data)))
+
+	return ok && len(pathMajor) == 0, nil
+}
+
+// moduleDir returns the directory of the module at the tagged commit, according to its go.mod file. From v2, the go.mod
+// file is looked up in the major subdirectory too, e.g. v2/go.mod for the tag v2.0.0. The modules without go.mod file
+// are accepted only at the root of the repository and before v2, like the go command does.
+func moduleDir(c *object.Commit, pathVersion string) (string, bool, error) {
+	dir, version := ".", pathVersion
+
+	if i := strings.LastIndex(pathVersion, "/"); i >= 0 {
+		dir, version = pathVersion[:i], pathVersion[i+1:]
+	}
+
+	v, err := module.ParseVersion(version)
+	if err != nil {
+		return "", false, nil // nolint: nilerr
+	}
+
+	if v.Major > 1 {
+		majorDir := path.Join(dir, fmt.Sprintf("v%d", v.Major))
+
+		ok, err := checkGoMod(c, path.Join(majorDir, goMod), version)
+		if ok || err != nil {
+			return majorDir, ok, err
+		}
+	}
+
+	f, err := c.File(path.Join(dir, goMod))
+	if errors.Is(err, object.ErrFileNotFound) {
+		return dir, dir == "." && v.Major < 2, nil
+	}
+
+	if err != nil {
+		return "", false, fmt.Errorf("could not get %s: %w", goMod, err)
+	}
+
+	ok, err := goModMatches(f, version)
+
+	return dir, ok, err
+}
+
+// checkGoMod checks whether the go.mod file exists and matches the version.
+func checkGoMod(c *object.Commit, file, version string) (bool, error) {
+	f, err := c.File(file)
+	if errors.Is(err, object.ErrFileNotFound) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("could not get %s: %w", file, err)
+	}
+
+	return goModMatches(f, version)
+}
+
+// goModMatches checks whether the module path in the go.mod file has the major version suffix of the version.
+func goModMatches(f *object.File, version string) (bool, error) {
+	data, err := f.Contents()
+	if err != nil {
+		return false, fmt.Errorf("could not read %s: %w", f.Name, err)
+	}
+
+	modPath := modfile.ModulePath([]byte(data))
+	if len(modPath) == 0 {
+		return false, nil
+	}
+
+	_, pathMajor, ok := xmodule.SplitPathVersion(modPath)
+	if !ok {
+		return false, nil
+	}
+
+	return xmodule.CheckPathMajor(version, pathMajor) == nil, nil
+}
@@ -10,6 +10,7 @@ import (
 
 	gogit "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -25,7 +26,7 @@ var (
 func TestClone_Error_CouldNotClone(t *testing.T) {
 	t.Parallel()
 
-	_, _, err := git.Clone("not-found", "")
+	_, _, err := git.Clone(t.Context(), "not-found", "")
 
 	expected := `could not clone repository: repository not found`
 
@@ -37,13 +38,29 @@ func TestClone_Error_CouldNotResolveRef(t *testing.T) {
 
 	repo := mockRepository()(t)
 
-	_, _, err := git.Clone(repo, "unknown")
+	_, _, err := git.Clone(t.Context(), repo, "unknown")
 
 	expected := `could not resolve ref "unknown": reference not found`
 
 	assert.EqualError(t, err, expected)
 }
 
+func TestClone_Error_RemovesDirectory(t *testing.T) { // nolint: paralleltest
+	repo := mockRepository()(t)
+
+	// The temporary directories are created in TMPDIR.
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+
+	_, _, err := git.Clone(t.Context(), repo, "unknown")
+	require.Error(t, err)
+
+	entries, err := os.ReadDir(tmp)
+	require.NoError(t, err)
+
+	assert.Empty(t, entries)
+}
+
 func TestClone_Success(t *testing.T) {
 	t.Parallel()
 
@@ -74,7 +91,7 @@ func TestClone_Success(t *testing.T) {
 
 			repo := tc.mockRepository(t)
 
-			dir, r, err := git.Clone(repo, tc.ref)
+			dir, r, err := git.Clone(t.Context(), repo, tc.ref)
 
 			assert.NotEmpty(t, dir)
 			assert.NotNil(t, r)
@@ -164,7 +181,7 @@ func TestVersions_Success(t *testing.T) {
 		t.Run(tc.scenario, func(t *testing.T) {
 			t.Parallel()
 
-			_, r, err := git.Clone(dir, tc.ref)
+			_, r, err := git.Clone(t.Context(), dir, tc.ref)
 			require.NoError(t, err, "could not clone")
 
 			actual, err := git.Versions(r)
@@ -175,6 +192,136 @@ func TestVersions_Success(t *testing.T) {
 	}
 }
 
+func TestVersions_GoModAtTag(t *testing.T) {
+	t.Parallel()
+
+	dir := mockRepository(func(t *testing.T, r *gogit.Repository, dir string) {
+		t.Helper()
+
+		// v0.1.0: No go.mod file.
+		writeFile(t, filepath.Join(dir, "example.go"), "package repository\n")
+		commitAndPush(t, r, "Initial code")
+		tagHead(t, r, "v0.1.0")
+
+		writeGoMod(t, dir, "host.tld/repository")
+		writeGoMod(t, filepath.Join(dir, "old"), "host.tld/repository/old")
+		writeFile(t, filepath.Join(dir, "docs", "README.md"), "")
+		commitAndPush(t, r, "Add modules")
+
+		tagHead(t, r, "old/v1.3.0")
+		// No go.mod file, kept.
+		tagHead(t, r, "docs/v1.0.0")
+		// Missing major suffix, kept.
+		tagHead(t, r, "v2.0.0")
+
+		// Remove the submodule.
+		require.NoError(t, os.RemoveAll(filepath.Join(dir, "old")))
+
+		writeGoMod(t, dir, "host.tld/repository/v3")
+		commitAndPush(t, r, "Remove old and bump to v3")
+
+		tagHead(t, r, "v3.0.0")
+		// The go.mod file declares another major version.
+		tagHead(t, r, "v4.0.0")
+	})(t)
+
+	_, r, err := git.Clone(t.Context(), dir, "")
+	require.NoError(t, err, "could not clone")
+
+	actual, err := git.Versions(r)
+	require.NoError(t, err, "could not get versions")
+
+	expected := []string{"docs/v1.0.0", "old/v1.3.0", "v0.1.0", "v2.0.0", "v3.0.0"}
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestVersions_Ancestry(t *testing.T) {
+	t.Parallel()
+
+	dir := mockRepository(func(t *testing.T, r *gogit.Repository, dir string) {
+		t.Helper()
+
+		ts := now()
+
+		writeGoMod(t, dir, "host.tld/repository")
+		commitAndPushAt(t, r, "Initial module", ts)
+		tagHead(t, r, "v0.1.0")
+
+		// Clock skew: the commit is newer than HEAD.
+		writeFile(t, filepath.Join(dir, "VERSION"), "v0.2.0")
+		commitAndPushAt(t, r, "Bump VERSION", ts.Add(time.Hour))
+		lightweightTagHead(t, r, "v0.2.0")
+
+		// Tag on another branch, older than HEAD.
+		checkoutBranch(t, r, "feature", true)
+
+		writeFile(t, filepath.Join(dir, "FEATURE"), "")
+		commitAt(t, r, "Add feature", ts.Add(-2*time.Hour))
+		tagHead(t, r, "v0.3.0")
+
+		err := r.Push(&gogit.PushOptions{RefSpecs: []config.RefSpec{"refs/heads/*:refs/heads/*"}})
+		require.NoError(t, err, "could not push")
+
+		checkoutBranch(t, r, "master", false)
+
+		writeFile(t, filepath.Join(dir, "README.md"), "")
+		commitAndPushAt(t, r, "Add README.md", ts.Add(-time.Hour))
+	})(t)
+
+	testCases := []struct {
+		scenario string
+		ref      string
+		all      bool
+		expected []string
+	}{
+		{
+			scenario: "head",
+			expected: []string{"v0.1.0", "v0.2.0"},
+		},
+		{
+			scenario: "tag on another branch",
+			ref:      "v0.3.0",
+			expected: []string{"v0.1.0", "v0.2.0", "v0.3.0"},
+		},
+		{
+			scenario: "lightweight tag",
+			ref:      "v0.2.0",
+			expected: []string{"v0.1.0", "v0.2.0"},
+		},
+		{
+			scenario: "annotated tag",
+			ref:      "v0.1.0",
+			expected: []string{"v0.1.0"},
+		},
+		{
+			scenario: "all tags",
+			ref:      "v0.1.0",
+			all:      true,
+			expected: []string{"v0.1.0", "v0.2.0", "v0.3.0"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			_, r, err := git.Clone(t.Context(), dir, tc.ref)
+			require.NoError(t, err, "could not clone")
+
+			versions := git.Versions
+			if tc.all {
+				versions = git.AllVersions
+			}
+
+			actual, err := versions(r)
+			require.NoError(t, err, "could not get versions")
+
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
 func mockRepository(mockers ...func(t *testing.T, r *gogit.Repository, dir string)) func(t *testing.T) string {
 	return func(t *testing.T) string {
 		t.Helper()
@@ -389,14 +536,18 @@ func ShortName() string {
 func commit(t *testing.T, r *gogit.Repository, message string) {
 	t.Helper()
 
+	commitAt(t, r, message, now())
+}
+
+func commitAt(t *testing.T, r *gogit.Repository, message string, ts time.Time) {
+	t.Helper()
+
 	w, err := r.Worktree()
 	require.NoError(t, err, "could not get worktree")
 
 	_, err = w.Add(".")
 	require.NoError(t, err, "could not stage files")
 
-	ts := now()
-
 	_, err = w.Commit(message, &gogit.CommitOptions{
 		Author:    sign(ts),
 		Committer: sign(ts),
@@ -413,6 +564,43 @@ func commitAndPush(t *testing.T, r *gogit.Repository, message string) {
 	require.NoError(t, err, "could not push")
 }
 
+func commitAndPushAt(t *testing.T, r *gogit.Repository, message string, ts time.Time) {
+	t.Helper()
+
+	commitAt(t, r, message, ts)
+
+	err := r.Push(&gogit.PushOptions{})
+	require.NoError(t, err, "could not push")
+}
+
+func checkoutBranch(t *testing.T, r *gogit.Repository, branch string, create bool) {
+	t.Helper()
+
+	w, err := r.Worktree()
+	require.NoError(t, err, "could not get worktree")
+
+	err = w.Checkout(&gogit.CheckoutOptions{
+		Branch: plumbing.NewBranchReferenceName(branch),
+		Create: create,
+	})
+	require.NoError(t, err, "could not checkout branch")
+}
+
+func lightweightTagHead(t *testing.T, r *gogit.Repository, tag string) {
+	t.Helper()
+
+	h, err := r.Head()
+	require.NoError(t, err, "could not get head")
+
+	_, err = r.CreateTag(tag, h.Hash(), nil)
+	require.NoError(t, err, "could not tag")
+
+	err = r.Push(&gogit.PushOptions{
+		RefSpecs: []config.RefSpec{"refs/tags/*:refs/tags/*"},
+	})
+	require.NoError(t, err, "could not push")
+}
+
 func tagHead(t *testing.T, r *gogit.Repository, tag string) {
 	t.Helper()
 
@@ -1,49 +1,171 @@
 package git
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/go-git/go-git/v5"
+
 	"go.nhat.io/vanityrender/internal/module"
+	"go.nhat.io/vanityrender/internal/repoinfo"
+)
+
+var (
+	_ module.Finder       = (*ModuleFinder)(nil)
+	_ module.ReadmeFinder = (*ModuleFinder)(nil)
 )
 
 // ModuleFinder finds modules in a repository.
-type ModuleFinder struct{}
+type ModuleFinder struct {
+	cloner Cloner
+
+	onInvalidGoMod func(err *module.GoModError)
+}
 
 // Find finds modules in a repository.
-func (f *ModuleFinder) Find(loc, ref string) (map[module.Path]module.Version, error) {
-	dir, r, err := Clone(loc, ref)
+func (f *ModuleFinder) Find(ctx context.Context, src module.Source) (map[module.Path]module.Version, error) {
+	dir, r, err := f.cloner.Clone(ctx, src.Repository, src.Ref)
 	if err != nil {
 		return nil, err
 	}
 
 	versions := []string{"v0.0.0"}
 
-	taggedVersions, err := Versions(r)
+	listVersions := Versions
+	if src.AllTags {
+		listVersions = AllVersions
+	}
+
+	taggedVersions, err := listVersions(r)
 	if err != nil {
 		return nil, err
 	}
 
 	versions = append(versions, taggedVersions...)
 
-	goModVersions, err := module.FindVersions(dir)
+	var findOpts []module.FindVersionsOption
+
+	if f.onInvalidGoMod != nil {
+		findOpts = append(findOpts, module.WithInvalidGoModHandler(func(err *module.GoModError) {
+			err.Repository = src.Repository
+
+			f.onInvalidGoMod(err)
+		}))
+	}
+
+	goModVersions, err := module.FindVersions(dir, src.Filter, findOpts...)
 	if err != nil {
+		var modErr *module.GoModError
+		if errors.As(err, &modErr) {
+			modErr.Repository = src.Repository
+		}
+
 		return nil, err // nolint: wrapcheck
 	}
 
 	versions = append(versions, goModVersions...)
 
+	return pathVersions(versions, src.Filter), nil
+}
+
+// FindReadmes reads the README files of the modules at the HEAD of the clone. The README file of a major version is
+// read from its subdirectory, e.g. v2/README.md, and then from the directory of the module, for the major branch layout.
+func (f *ModuleFinder) FindReadmes(ctx context.Context, src module.Source, paths []module.Path) (map[module.Path]module.Readme, error) {
+	_, r, err := f.cloner.Clone(ctx, src.Repository, src.Ref)
+	if err != nil {
+		return nil, err
+	}
+
+	return readmes(r, paths)
+}
+
+// readmes reads the README files of the modules at the HEAD of the repository.
+func readmes(r *git.Repository, paths []module.Path) (map[module.Path]module.Readme, error) {
+	result := make(map[module.Path]module.Readme, len(paths))
+
+	for _, p := range paths {
+		dirs := []string{string(p)}
+
+		if base := module.PathWithoutVersion(p); base != string(p) {
+			dirs = append(dirs, base)
+		}
+
+		for _, dir := range dirs {
+			name, content, err := ReadHeadFile(r, dir, repoinfo.ReadmeRank)
+			if err != nil {
+				return nil, fmt.Errorf("could not read readme of %q: %w", p, err)
+			}
+
+			if len(name) > 0 {
+				result[p] = module.Readme{Dir: dir, Name: name, Content: content}
+
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// pathVersions returns the latest version of each module path that is selected by the filter.
+func pathVersions(versions []string, filter module.PathFilter) map[module.Path]module.Version {
 	result := make(map[module.Path]module.Version, len(versions))
 
 	for _, s := range versions {
+		if !filter.Match(path.Dir(s)) {
+			continue
+		}
+
 		k, v := module.PathVersion(s)
+		if len(k) == 0 {
+			continue
+		}
 
 		if curVersion, ok := result[k]; !ok || curVersion.LessThan(v) {
 			result[k] = v
 		}
 	}
 
-	return result, nil
+	return result
 }
 
 // NewModuleFinder returns a new module finder.
-func NewModuleFinder() *ModuleFinder {
-	return &ModuleFinder{}
+func NewModuleFinder(opts ...ModuleFinderOption) *ModuleFinder {
+	f := &ModuleFinder{
+		cloner: NewCachedCloner(NewRetryCloner(ClonerFunc(Clone))),
+	}
+
+	for _, o := range opts {
+		o.applyModuleFinderOption(f)
+	}
+
+	return f
+}
+
+// ModuleFinderOption is an option to configure ModuleFinder.
+type ModuleFinderOption interface {
+	applyModuleFinderOption(f *ModuleFinder)
+}
+
+type moduleFinderOptionFunc func(f *ModuleFinder)
+
+func (fn moduleFinderOptionFunc) applyModuleFinderOption(f *ModuleFinder) {
+	fn(f)
+}
+
+// WithCloner sets the cloner that is used to fetch the repositories.
+func WithCloner(c Cloner) ModuleFinderOption {
+	return moduleFinderOptionFunc(func(f *ModuleFinder) {
+		f.cloner = c
+	})
+}
+
+// WithInvalidGoModHandler skips the modules whose go.mod files could not be read, instead of failing, and reports them to
+// the handler.
+func WithInvalidGoModHandler(fn func(err *module.GoModError)) ModuleFinderOption {
+	return moduleFinderOptionFunc(func(f *ModuleFinder) {
+		f.onInvalidGoMod = fn
+	})
 }
@@ -16,7 +16,7 @@ func TestModuleFinder_Find_Error_CouldNotClone(t *testing.T) {
 	t.Parallel()
 
 	f := git.NewModuleFinder()
-	_, err := f.Find("not-found", "")
+	_, err := f.Find(t.Context(), module.Source{Repository: "not-found"})
 
 	expected := `could not clone repository: repository not found`
 
@@ -27,22 +27,177 @@ func TestModuleFinder_Find_Success(t *testing.T) {
 	t.Parallel()
 
 	dir := mockRepository(initExampleModule(), bumpExampleModule())(t)
-	f := git.NewModuleFinder()
+	f := git.NewModuleFinder(git.WithCloner(fakeCloner(map[string]string{
+		"https://github.com/org/repository": dir,
+	})))
+
+	actual, err := f.Find(t.Context(), module.Source{Repository: "https://github.com/org/repository"})
+	require.NoError(t, err, "could not find modules")
+
+	expected := map[module.Path]module.Version{
+		".":          module.NewVersion(1, 0, 0),
+		"v2":         module.NewVersion(2, 10, 0),
+		"contrib":    module.NewVersion(0, 2, 0),
+		"contrib/v2": module.NewVersion(2, 0, 0),
+		"test":       module.NewVersion(0, 2, 0),
+	}
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestModuleFinder_Find_Filter(t *testing.T) {
+	t.Parallel()
+
+	dir := mockRepository(initExampleModule(), bumpExampleModule())(t)
+	f := git.NewModuleFinder(git.WithCloner(fakeCloner(map[string]string{
+		"https://github.com/org/repository": dir,
+	})))
+
+	actual, err := f.Find(t.Context(), module.Source{
+		Repository: "https://github.com/org/repository",
+		Filter:     module.PathFilter{Exclude: []string{"test"}},
+	})
+	require.NoError(t, err, "could not find modules")
+
+	expected := map[module.Path]module.Version{
+		".":          module.NewVersion(1, 0, 0),
+		"v2":         module.NewVersion(2, 10, 0),
+		"contrib":    module.NewVersion(0, 2, 0),
+		"contrib/v2": module.NewVersion(2, 0, 0),
+	}
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestModuleFinder_Find_MajorSubdirectory(t *testing.T) {
+	t.Parallel()
+
+	dir := mockRepository(func(t *testing.T, r *gogit.Repository, dir string) {
+		t.Helper()
 
-	actual, err := f.Find(dir, "")
+		writeGoMod(t, dir, "host.tld/repository")
+		commitAndPush(t, r, "Init module")
+		tagHead(t, r, "v1.0.0")
+
+		writeGoMod(t, filepath.Join(dir, "v2"), "host.tld/repository/v2")
+		commitAndPush(t, r, "Add v2")
+		tagHead(t, r, "v2.0.0")
+
+		writeFile(t, filepath.Join(dir, "v2", "VERSION"), "v2.1.0")
+		commitAndPush(t, r, "Bump v2")
+		tagHead(t, r, "v2.1.0")
+
+		writeFile(t, filepath.Join(dir, "VERSION"), "v1.1.0")
+		commitAndPush(t, r, "Bump v1")
+		tagHead(t, r, "v1.1.0")
+	})(t)
+
+	f := git.NewModuleFinder(git.WithCloner(fakeCloner(map[string]string{
+		"https://github.com/org/repository": dir,
+	})))
+
+	actual, err := f.Find(t.Context(), module.Source{Repository: "https://github.com/org/repository"})
 	require.NoError(t, err, "could not find modules")
 
 	expected := map[module.Path]module.Version{
-		".":          module.NewVersionFromString("v1.0.0"),
-		"v2":         module.NewVersionFromString("v2.10.0"),
-		"contrib":    module.NewVersionFromString("v0.2.0"),
-		"contrib/v2": module.NewVersionFromString("v2.0.0"),
-		"test":       module.NewVersionFromString("v0.2.0"),
+		".":  module.NewVersion(1, 1, 0),
+		"v2": module.NewVersion(2, 1, 0),
 	}
 
 	assert.Equal(t, expected, actual)
 }
 
+func TestModuleFinder_FindReadmes(t *testing.T) {
+	t.Parallel()
+
+	dir := mockRepository(func(t *testing.T, r *gogit.Repository, dir string) {
+		t.Helper()
+
+		writeGoMod(t, dir, "host.tld/repository")
+		writeFile(t, filepath.Join(dir, "README.md"), "# Repository\n")
+		writeGoMod(t, filepath.Join(dir, "v2"), "host.tld/repository/v2")
+		writeFile(t, filepath.Join(dir, "v2", "README"), "Repository v2\n")
+		writeGoMod(t, filepath.Join(dir, "contrib"), "host.tld/repository/contrib")
+		writeFile(t, filepath.Join(dir, "contrib", "readme.md"), "# Contrib\n")
+		writeGoMod(t, filepath.Join(dir, "test"), "host.tld/repository/test")
+		commitAndPush(t, r, "Init modules")
+	})(t)
+
+	f := git.NewModuleFinder(git.WithCloner(fakeCloner(map[string]string{
+		"https://github.com/org/repository": dir,
+	})))
+
+	actual, err := f.FindReadmes(t.Context(), module.Source{Repository: "https://github.com/org/repository"},
+		[]module.Path{".", "v2", "contrib", "contrib/v3", "test"},
+	)
+	require.NoError(t, err, "could not find readmes")
+
+	expected := map[module.Path]module.Readme{
+		".":          {Dir: ".", Name: "README.md", Content: []byte("# Repository\n")},
+		"v2":         {Dir: "v2", Name: "README", Content: []byte("Repository v2\n")},
+		"contrib":    {Dir: "contrib", Name: "readme.md", Content: []byte("# Contrib\n")},
+		"contrib/v3": {Dir: "contrib", Name: "readme.md", Content: []byte("# Contrib\n")},
+	}
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestModuleFinder_FindReadmes_Error_CouldNotClone(t *testing.T) {
+	t.Parallel()
+
+	f := git.NewModuleFinder()
+	_, err := f.FindReadmes(t.Context(), module.Source{Repository: "not-found"}, []module.Path{"."})
+
+	assert.EqualError(t, err, `could not clone repository: repository not found`)
+}
+
+func TestModuleFinder_Find_InvalidGoMod(t *testing.T) {
+	t.Parallel()
+
+	dir := mockRepository(func(t *testing.T, r *gogit.Repository, dir string) {
+		t.Helper()
+
+		writeGoMod(t, dir, "host.tld/repository")
+		writeFile(t, filepath.Join(dir, "examples", "go.mod"), "module host.tld/repository/examples\nunknown directive\n")
+		commitAndPush(t, r, "Add examples")
+	})(t)
+
+	cloner := fakeCloner(map[string]string{"https://github.com/org/repository": dir})
+	src := module.Source{Repository: "https://github.com/org/repository"}
+
+	t.Run("abort", func(t *testing.T) {
+		t.Parallel()
+
+		f := git.NewModuleFinder(git.WithCloner(cloner))
+
+		actual, err := f.Find(t.Context(), src)
+
+		expected := "https://github.com/org/repository: invalid go.mod file: examples/go.mod:2: unknown directive: unknown"
+
+		assert.Nil(t, actual)
+		assert.EqualError(t, err, expected)
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		t.Parallel()
+
+		var skipped []*module.GoModError
+
+		f := git.NewModuleFinder(git.WithCloner(cloner), git.WithInvalidGoModHandler(func(err *module.GoModError) {
+			skipped = append(skipped, err)
+		}))
+
+		actual, err := f.Find(t.Context(), src)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[module.Path]module.Version{".": module.NewVersion(0, 0, 0)}, actual)
+
+		require.Len(t, skipped, 1)
+		assert.Equal(t, "https://github.com/org/repository", skipped[0].Repository)
+		assert.Equal(t, "examples/go.mod", skipped[0].File)
+	})
+}
+
 func bumpExampleModule() func(t *testing.T, r *gogit.Repository, dir string) {
 	return func(t *testing.T, r *gogit.Repository, dir string) {
 		t.Helper()
@@ -0,0 +1,295 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/gitattributes"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"golang.org/x/mod/zip"
+
+	"go.nhat.io/vanityrender/internal/module"
+)
+
+const (
+	gitattributesFile = ".gitattributes"
+	licenseFile       = "LICENSE"
+	exportIgnore      = "export-ignore"
+)
+
+// ModuleVersion is a version of a module at a tagged commit.
+type ModuleVersion struct {
+	// Version is the version of the module, without the directory prefix of the tag, e.g. v2.1.0.
+	Version string
+	// Dir is the directory of the module in the repository.
+	Dir string
+	// Time is the time of the tagged commit.
+	Time time.Time
+	// GoMod is the content of the go.mod file, nil if the module does not have one.
+	GoMod []byte
+	// Files are the files of the module directory, in the format of golang.org/x/mod/zip.
+	Files []zip.File
+}
+
+// ModuleVersions returns the versions of the module at the path in the repository, e.g. "contrib/v2", from the tags,
+// see Versions and AllVersions. The versions are in the same order as the tags.
+func ModuleVersions(r *git.Repository, tags []string, p module.Path) ([]ModuleVersion, error) {
+	var result []ModuleVersion
+
+	for _, tag := range tags {
+		if tp, _ := module.PathVersion(tag); tp != p {
+			continue
+		}
+
+		v, ok, err := moduleVersion(r, tag)
+		if err != nil {
+			return nil, fmt.Errorf("could not get module version %q: %w", tag, err)
+		}
+
+		if ok {
+			result = append(result, v)
+		}
+	}
+
+	return result, nil
+}
+
+func moduleVersion(r *git.Repository, tag string) (ModuleVersion, bool, error) {
+	ref, err := r.Tag(tag)
+	if err != nil {
+		return ModuleVersion{}, false, fmt.Errorf("could not get tag: %w", err)
+	}
+
+	c, err := tagCommit(r, ref)
+	if err != nil || c == nil {
+		return ModuleVersion{}, false, err
+	}
+
+	dir, ok, err := moduleDir(c, tag)
+	if err != nil || !ok {
+		return ModuleVersion{}, false, err
+	}
+
+	v := ModuleVersion{
+		Version: tag[strings.LastIndex(tag, "/")+1:],
+		Dir:     dir,
+		Time:    c.Committer.When.UTC(),
+	}
+
+	if v.GoMod, err = readFile(c, path.Join(dir, goMod)); err != nil {
+		return ModuleVersion{}, false, err
+	}
+
+	if v.Files, err = moduleFiles(c, dir); err != nil {
+		return ModuleVersion{}, false, err
+	}
+
+	return v, true, nil
+}
+
+// readFile reads a file at the commit. It returns nil if the file does not exist.
+func readFile(c *object.Commit, name string) ([]byte, error) {
+	f, err := c.File(name)
+	if errors.Is(err, object.ErrFileNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not get %s: %w", name, err)
+	}
+
+	data, err := f.Contents()
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", name, err)
+	}
+
+	return []byte(data), nil
+}
+
+// moduleFiles returns the files in the directory at the commit, like `git archive` does for the go command: the files
+// with the export-ignore attribute are left out, and a module in a subdirectory without a LICENSE file gets the one of
+// the repository root. The files of the nested modules are excluded later by zip.Create.
+func moduleFiles(c *object.Commit, dir string) ([]zip.File, error) {
+	root, err := c.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("could not get tree: %w", err)
+	}
+
+	ignored, err := exportIgnored(root)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := root
+
+	if dir != "." {
+		if tree, err = root.Tree(dir); err != nil {
+			return nil, fmt.Errorf("could not get tree %q: %w", dir, err)
+		}
+	}
+
+	var (
+		files      []zip.File
+		hasLicense bool
+	)
+
+	err = tree.Files().ForEach(func(f *object.File) error {
+		if ignored(path.Join(dir, f.Name)) {
+			return nil
+		}
+
+		if f.Name == licenseFile {
+			hasLicense = true
+		}
+
+		files = append(files, &zipFile{file: f, name: f.Name, time: c.Committer.When})
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not list files: %w", err)
+	}
+
+	if dir == "." || hasLicense {
+		return files, nil
+	}
+
+	license, err := root.File(licenseFile)
+	if errors.Is(err, object.ErrFileNotFound) {
+		return files, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not get %s: %w", licenseFile, err)
+	}
+
+	return append(files, &zipFile{file: license, name: licenseFile, time: c.Committer.When}), nil
+}
+
+// exportIgnored reads the .gitattributes files of the tree, and returns whether a file or one of its parent directories
+// has the export-ignore attribute.
+func exportIgnored(tree *object.Tree) (func(name string) bool, error) {
+	// The attributes are indexed by the depth of their directory, the deeper ones take precedence.
+	var attrs [][]gitattributes.MatchAttribute
+
+	err := tree.Files().ForEach(func(f *object.File) error {
+		if path.Base(f.Name) != gitattributesFile {
+			return nil
+		}
+
+		r, err := f.Reader()
+		if err != nil {
+			return fmt.Errorf("could not read %s: %w", f.Name, err)
+		}
+
+		defer r.Close() // nolint: errcheck
+
+		var domain []string
+
+		if d := path.Dir(f.Name); d != "." {
+			domain = strings.Split(d, "/")
+		}
+
+		a, err := gitattributes.ReadAttributes(r, domain, len(domain) == 0)
+		if err != nil {
+			return fmt.Errorf("could not parse %s: %w", f.Name, err)
+		}
+
+		for len(attrs) <= len(domain) {
+			attrs = append(attrs, nil)
+		}
+
+		attrs[len(domain)] = append(attrs[len(domain)], a...)
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not list %s files: %w", gitattributesFile, err)
+	}
+
+	if len(attrs) == 0 {
+		return func(string) bool { return false }, nil
+	}
+
+	m := gitattributes.NewMatcher(slices.Concat(attrs...))
+
+	return func(name string) bool {
+		parts := strings.Split(name, "/")
+
+		for i := 1; i <= len(parts); i++ {
+			if a, ok := m.Match(parts[:i], []string{exportIgnore}); ok && a[exportIgnore] != nil && a[exportIgnore].IsSet() {
+				return true
+			}
+		}
+
+		return false
+	}, nil
+}
+
+var (
+	_ zip.File    = (*zipFile)(nil)
+	_ fs.FileInfo = (*zipFile)(nil)
+)
+
+// zipFile is a zip.File of a git blob.
+type zipFile struct {
+	file *object.File
+	name string
+	time time.Time
+}
+
+func (f *zipFile) Path() string {
+	return f.name
+}
+
+func (f *zipFile) Lstat() (fs.FileInfo, error) {
+	return f, nil
+}
+
+func (f *zipFile) Open() (io.ReadCloser, error) {
+	return f.file.Reader() // nolint: wrapcheck
+}
+
+func (f *zipFile) Name() string {
+	return path.Base(f.name)
+}
+
+func (f *zipFile) Size() int64 {
+	return f.file.Size
+}
+
+func (f *zipFile) Mode() fs.FileMode {
+	switch f.file.Mode {
+	case filemode.Symlink:
+		return fs.ModeSymlink | 0o777
+
+	case filemode.Executable:
+		return 0o755
+
+	case filemode.Regular, filemode.Deprecated:
+		return 0o644
+
+	default:
+		return fs.ModeIrregular
+	}
+}
+
+func (f *zipFile) ModTime() time.Time {
+	return f.time
+}
+
+func (f *zipFile) IsDir() bool {
+	return false
+}
+
+func (f *zipFile) Sys() any {
+	return nil
+}
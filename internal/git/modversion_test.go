@@ -0,0 +1,233 @@
+package git_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	xmodule "golang.org/x/mod/module"
+	"golang.org/x/mod/sumdb/dirhash"
+	"golang.org/x/mod/zip"
+
+	"go.nhat.io/vanityrender/internal/git"
+	"go.nhat.io/vanityrender/internal/module"
+)
+
+func TestModuleVersions(t *testing.T) {
+	t.Parallel()
+
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	dir := mockRepository(func(t *testing.T, r *gogit.Repository, dir string) {
+		t.Helper()
+
+		writeFile(t, filepath.Join(dir, "legacy.go"), "package repository\n")
+		commitAndPushAt(t, r, "Legacy", ts)
+		tagHead(t, r, "v0.1.0")
+
+		writeGoMod(t, dir, "host.tld/repository")
+		writeGoMod(t, filepath.Join(dir, "contrib"), "host.tld/repository/contrib")
+		commitAndPushAt(t, r, "Add contrib", ts.Add(time.Hour))
+		tagHead(t, r, "v1.0.0")
+		tagHead(t, r, "contrib/v0.1.0")
+
+		writeGoMod(t, filepath.Join(dir, "v2"), "host.tld/repository/v2")
+		writeFile(t, filepath.Join(dir, "v2", "v2.go"), "package repository\n")
+		commitAndPushAt(t, r, "Add v2", ts.Add(2*time.Hour))
+		tagHead(t, r, "v2.0.0")
+	})(t)
+
+	r, err := gogit.PlainOpen(dir)
+	require.NoError(t, err)
+
+	tags, err := git.Versions(r)
+	require.NoError(t, err)
+
+	t.Run("root", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := git.ModuleVersions(r, tags, ".")
+		require.NoError(t, err)
+
+		require.Len(t, actual, 2)
+
+		assert.Equal(t, "v0.1.0", actual[0].Version)
+		assert.Equal(t, ".", actual[0].Dir)
+		assert.Equal(t, ts, actual[0].Time)
+		assert.Nil(t, actual[0].GoMod)
+		assert.ElementsMatch(t, []string{".gitignore", "legacy.go"}, filePaths(actual[0]))
+
+		assert.Equal(t, "v1.0.0", actual[1].Version)
+		assert.Equal(t, "module host.tld/repository\n\ngo 1.18\n", string(actual[1].GoMod))
+		assert.ElementsMatch(t, []string{".gitignore", "legacy.go", "go.mod", "contrib/go.mod"}, filePaths(actual[1]))
+	})
+
+	t.Run("submodule", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := git.ModuleVersions(r, tags, "contrib")
+		require.NoError(t, err)
+
+		require.Len(t, actual, 1)
+
+		assert.Equal(t, "v0.1.0", actual[0].Version)
+		assert.Equal(t, "contrib", actual[0].Dir)
+		assert.Equal(t, []string{"go.mod"}, filePaths(actual[0]))
+	})
+
+	t.Run("major subdirectory", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := git.ModuleVersions(r, tags, "v2")
+		require.NoError(t, err)
+
+		require.Len(t, actual, 1)
+
+		assert.Equal(t, "v2.0.0", actual[0].Version)
+		assert.Equal(t, "v2", actual[0].Dir)
+		assert.Equal(t, ts.Add(2*time.Hour), actual[0].Time)
+		assert.ElementsMatch(t, []string{"go.mod", "v2.go"}, filePaths(actual[0]))
+
+		for _, f := range actual[0].Files {
+			if f.Path() != "v2.go" {
+				continue
+			}
+
+			fi, err := f.Lstat()
+			require.NoError(t, err)
+
+			assert.True(t, fi.Mode().IsRegular())
+
+			rc, err := f.Open()
+			require.NoError(t, err)
+
+			data, err := io.ReadAll(rc)
+			require.NoError(t, err)
+			require.NoError(t, rc.Close())
+
+			assert.Equal(t, "package repository\n", string(data))
+			assert.Equal(t, int64(len(data)), fi.Size())
+		}
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := git.ModuleVersions(r, tags, module.Path("unknown"))
+		require.NoError(t, err)
+
+		assert.Empty(t, actual)
+	})
+}
+
+func TestModuleVersions_SameHashAsGo(t *testing.T) {
+	t.Parallel()
+
+	// zip.CreateFromVCS runs git archive in the working directory of the repository.
+	var workDir string
+
+	dir := mockRepository(func(t *testing.T, r *gogit.Repository, dir string) {
+		t.Helper()
+
+		workDir = dir
+
+		writeFile(t, filepath.Join(dir, "LICENSE"), "MIT License\n")
+		writeFile(t, filepath.Join(dir, ".gitattributes"), "testdata export-ignore\n*.secret export-ignore\n")
+		writeGoMod(t, dir, "host.tld/repository")
+		writeGoMod(t, filepath.Join(dir, "contrib"), "host.tld/repository/contrib")
+		writeFile(t, filepath.Join(dir, "contrib", "contrib.go"), "package contrib\n")
+		writeFile(t, filepath.Join(dir, "contrib", "notes.secret"), "secret\n")
+		writeFile(t, filepath.Join(dir, "contrib", "testdata", "data.txt"), "data\n")
+		writeFile(t, filepath.Join(dir, "contrib", "internal", ".gitattributes"), "*.txt export-ignore\n")
+		writeFile(t, filepath.Join(dir, "contrib", "internal", "doc.txt"), "doc\n")
+		writeFile(t, filepath.Join(dir, "contrib", "internal", "internal.go"), "package internal\n")
+		writeGoMod(t, filepath.Join(dir, "v2"), "host.tld/repository/v2")
+		writeFile(t, filepath.Join(dir, "v2", "LICENSE"), "Apache License\n")
+		commitAndPush(t, r, "Add modules")
+		tagHead(t, r, "contrib/v0.1.0")
+		tagHead(t, r, "v2.0.0")
+	})(t)
+
+	r, err := gogit.PlainOpen(dir)
+	require.NoError(t, err)
+
+	tags, err := git.Versions(r)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		scenario string
+		path     module.Path
+		tag      string
+		expected []string
+	}{
+		{
+			scenario: "submodule with the license of the repository",
+			path:     "contrib",
+			tag:      "contrib/v0.1.0",
+			expected: []string{"LICENSE", "contrib.go", "go.mod", "internal/.gitattributes", "internal/internal.go"},
+		},
+		{
+			scenario: "major subdirectory with its own license",
+			path:     "v2",
+			tag:      "v2.0.0",
+			expected: []string{"LICENSE", "go.mod"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := git.ModuleVersions(r, tags, tc.path)
+			require.NoError(t, err)
+			require.Len(t, actual, 1)
+
+			assert.ElementsMatch(t, tc.expected, filePaths(actual[0]))
+
+			m := xmodule.Version{Path: "host.tld/repository/" + string(tc.path), Version: actual[0].Version}
+
+			expected := filepath.Join(t.TempDir(), "expected.zip")
+			writeZip(t, expected, func(w io.Writer) error {
+				return zip.CreateFromVCS(w, m, workDir, tc.tag, string(tc.path))
+			})
+
+			got := filepath.Join(t.TempDir(), "actual.zip")
+			writeZip(t, got, func(w io.Writer) error {
+				return zip.Create(w, m, actual[0].Files)
+			})
+
+			expectedHash, err := dirhash.HashZip(expected, dirhash.Hash1)
+			require.NoError(t, err)
+
+			actualHash, err := dirhash.HashZip(got, dirhash.Hash1)
+			require.NoError(t, err)
+
+			assert.Equal(t, expectedHash, actualHash)
+		})
+	}
+}
+
+func filePaths(v git.ModuleVersion) []string {
+	paths := make([]string, 0, len(v.Files))
+
+	for _, f := range v.Files {
+		paths = append(paths, f.Path())
+	}
+
+	return paths
+}
+
+func writeZip(t *testing.T, name string, create func(w io.Writer) error) {
+	t.Helper()
+
+	f, err := os.Create(filepath.Clean(name))
+	require.NoError(t, err)
+
+	require.NoError(t, create(f))
+	require.NoError(t, f.Close())
+}
@@ -0,0 +1,114 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/storage/memory"
+
+	"go.nhat.io/vanityrender/internal/module"
+)
+
+// RemoteVersions returns all the versions that are tagged in the remote repository, without cloning it.
+func RemoteVersions(ctx context.Context, url string) ([]string, error) {
+	rem := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{url},
+	})
+
+	refs, err := rem.ListContext(ctx, &git.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("could not list remote references: %w", err)
+	}
+
+	tagNames := make([]string, 0, len(refs))
+
+	for _, ref := range refs {
+		if !ref.Name().IsTag() {
+			continue
+		}
+
+		if version := ref.Name().Short(); module.PathVersionRegExp.MatchString(version) {
+			tagNames = append(tagNames, version)
+		}
+	}
+
+	sort.Strings(tagNames)
+
+	return tagNames, nil
+}
+
+var (
+	_ module.Finder       = (*RemoteModuleFinder)(nil)
+	_ module.ReadmeFinder = (*RemoteModuleFinder)(nil)
+)
+
+// RemoteModuleFinder finds modules by listing the tags of the remote repository, the module paths are derived from the
+// tag prefixes. Because it does not read the go.mod files, the submodules that have never been tagged are not found, and
+// the tags are not checked against the go.mod files at the tagged commits. All the tags are considered, as if
+// module.Source.AllTags was set.
+//
+// The fallback finder is used when the tags are not enough: the repository has no version tags, or a ref is set and
+// only the tags reachable from it must be considered.
+//
+// The README files are read from a shallow clone of the default branch, in memory, or by the fallback finder when a ref
+// is set.
+type RemoteModuleFinder struct {
+	fallback module.Finder
+}
+
+// Find finds modules in a repository.
+func (f *RemoteModuleFinder) Find(ctx context.Context, src module.Source) (map[module.Path]module.Version, error) {
+	if len(src.Ref) > 0 {
+		return f.fallback.Find(ctx, src)
+	}
+
+	taggedVersions, err := RemoteVersions(ctx, src.Repository)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(taggedVersions) == 0 {
+		return f.fallback.Find(ctx, src)
+	}
+
+	return pathVersions(append([]string{"v0.0.0"}, taggedVersions...), src.Filter), nil
+}
+
+// FindReadmes reads the README files of the modules at the HEAD of the remote repository, see ModuleFinder.FindReadmes.
+// When a ref is set, the fallback finder reads them because it has already cloned the repository, and there are none if
+// it is not a module.ReadmeFinder.
+func (f *RemoteModuleFinder) FindReadmes(ctx context.Context, src module.Source, paths []module.Path) (map[module.Path]module.Readme, error) {
+	if len(src.Ref) > 0 {
+		finder, ok := f.fallback.(module.ReadmeFinder)
+		if !ok {
+			return nil, nil // nolint: nilnil
+		}
+
+		return finder.FindReadmes(ctx, src, paths) // nolint: wrapcheck
+	}
+
+	r, err := git.CloneContext(ctx, memory.NewStorage(), nil, &git.CloneOptions{
+		URL:          src.Repository,
+		Depth:        1,
+		SingleBranch: true,
+		NoCheckout:   true,
+		Progress:     io.Discard,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch repository: %w", err)
+	}
+
+	return readmes(r, paths)
+}
+
+// NewRemoteModuleFinder returns a new module finder that uses the remote tags.
+func NewRemoteModuleFinder(fallback module.Finder) *RemoteModuleFinder {
+	return &RemoteModuleFinder{
+		fallback: fallback,
+	}
+}
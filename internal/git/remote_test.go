@@ -0,0 +1,189 @@
+package git_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/vanityrender/internal/git"
+	"go.nhat.io/vanityrender/internal/module"
+)
+
+func TestRemoteVersions_Error(t *testing.T) {
+	t.Parallel()
+
+	url := mockGitServer(t, t.TempDir()) + "/not-found"
+
+	_, err := git.RemoteVersions(t.Context(), url)
+
+	expected := `could not list remote references: repository not found: `
+
+	assert.EqualError(t, err, expected)
+}
+
+func TestRemoteVersions_Success(t *testing.T) {
+	t.Parallel()
+
+	dir := mockRepository(initExampleModule())(t)
+	url := mockGitServer(t, filepath.Dir(dir)) + "/" + filepath.Base(dir)
+
+	actual, err := git.RemoteVersions(t.Context(), url)
+	require.NoError(t, err)
+
+	expected := []string{
+		"contrib/v0.1.0", "contrib/v0.2.0",
+		"test/v0.1.0", "test/v0.2.0",
+		"v0.1.0", "v0.1.1", "v0.2.0", "v0.3.0", "v0.4.0", "v0.5.0",
+	}
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestRemoteModuleFinder_Find(t *testing.T) {
+	t.Parallel()
+
+	tagged := mockRepository(initExampleModule(), bumpExampleModule())(t)
+	untagged := mockRepository()(t)
+
+	fallbackResult := map[module.Path]module.Version{".": module.NewVersion(0, 0, 0)}
+
+	testCases := []struct {
+		scenario         string
+		dir              string
+		ref              string
+		expectedFallback bool
+		expectedResult   map[module.Path]module.Version
+		expectedError    string
+	}{
+		{
+			scenario:      "could not list",
+			dir:           filepath.Join(t.TempDir(), "not-found"),
+			expectedError: "could not list remote references: repository not found: ",
+		},
+		{
+			scenario:         "has ref",
+			dir:              tagged,
+			ref:              "v1.0.0",
+			expectedFallback: true,
+			expectedResult:   fallbackResult,
+		},
+		{
+			scenario:         "no tags",
+			dir:              untagged,
+			expectedFallback: true,
+			expectedResult:   fallbackResult,
+		},
+		{
+			scenario: "tags",
+			dir:      tagged,
+			expectedResult: map[module.Path]module.Version{
+				".":          module.NewVersion(1, 0, 0),
+				"v2":         module.NewVersion(2, 10, 0),
+				"contrib":    module.NewVersion(0, 2, 0),
+				"contrib/v2": module.NewVersion(2, 0, 0),
+				"test":       module.NewVersion(0, 2, 0),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			var fallbackCalled bool
+
+			fallback := moduleFinderFunc(func(context.Context, module.Source) (map[module.Path]module.Version, error) {
+				fallbackCalled = true
+
+				return fallbackResult, nil
+			})
+
+			url := mockGitServer(t, filepath.Dir(tc.dir)) + "/" + filepath.Base(tc.dir)
+
+			actual, err := git.NewRemoteModuleFinder(fallback).Find(t.Context(), module.Source{Repository: url, Ref: tc.ref})
+
+			assert.Equal(t, tc.expectedResult, actual)
+			assert.Equal(t, tc.expectedFallback, fallbackCalled)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestRemoteModuleFinder_FindReadmes(t *testing.T) {
+	t.Parallel()
+
+	dir := mockRepository(func(t *testing.T, r *gogit.Repository, dir string) {
+		t.Helper()
+
+		writeGoMod(t, dir, "host.tld/repository")
+		writeFile(t, filepath.Join(dir, "README.md"), "# Repository\n")
+		writeGoMod(t, filepath.Join(dir, "contrib"), "host.tld/repository/contrib")
+		writeFile(t, filepath.Join(dir, "contrib", "readme.md"), "# Contrib\n")
+		commitAndPush(t, r, "Init modules")
+		tagHead(t, r, "v1.0.0")
+	})(t)
+
+	url := mockGitServer(t, filepath.Dir(dir)) + "/" + filepath.Base(dir)
+
+	t.Run("default branch", func(t *testing.T) {
+		t.Parallel()
+
+		f := git.NewRemoteModuleFinder(moduleFinderFunc(func(context.Context, module.Source) (map[module.Path]module.Version, error) {
+			return nil, nil
+		}))
+
+		actual, err := f.FindReadmes(t.Context(), module.Source{Repository: url}, []module.Path{".", "v2", "contrib"})
+		require.NoError(t, err)
+
+		expected := map[module.Path]module.Readme{
+			".":       {Dir: ".", Name: "README.md", Content: []byte("# Repository\n")},
+			"v2":      {Dir: ".", Name: "README.md", Content: []byte("# Repository\n")},
+			"contrib": {Dir: "contrib", Name: "readme.md", Content: []byte("# Contrib\n")},
+		}
+
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("ref", func(t *testing.T) {
+		t.Parallel()
+
+		f := git.NewRemoteModuleFinder(git.NewModuleFinder(git.WithCloner(fakeCloner(map[string]string{
+			"https://github.com/org/repository": dir,
+		}))))
+
+		actual, err := f.FindReadmes(t.Context(), module.Source{Repository: "https://github.com/org/repository", Ref: "v1.0.0"}, []module.Path{"."})
+		require.NoError(t, err)
+
+		expected := map[module.Path]module.Readme{
+			".": {Dir: ".", Name: "README.md", Content: []byte("# Repository\n")},
+		}
+
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("could not fetch", func(t *testing.T) {
+		t.Parallel()
+
+		f := git.NewRemoteModuleFinder(git.NewModuleFinder())
+
+		actual, err := f.FindReadmes(t.Context(), module.Source{Repository: url + "-not-found"}, []module.Path{"."})
+
+		assert.Nil(t, actual)
+		assert.ErrorContains(t, err, "could not fetch repository: ")
+	})
+}
+
+type moduleFinderFunc func(ctx context.Context, src module.Source) (map[module.Path]module.Version, error)
+
+func (f moduleFinderFunc) Find(ctx context.Context, src module.Source) (map[module.Path]module.Version, error) {
+	return f(ctx, src)
+}
@@ -0,0 +1,137 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+const (
+	defaultMaxRetries     = 3
+	defaultInitialBackoff = time.Second
+	defaultMaxBackoff     = 30 * time.Second
+)
+
+// transientErrors are the errors of the connections that may go away by retrying.
+var transientErrors = []error{
+	io.ErrUnexpectedEOF,
+	syscall.ECONNREFUSED,
+	syscall.ECONNRESET,
+	syscall.ECONNABORTED,
+	syscall.EPIPE,
+	syscall.ETIMEDOUT,
+}
+
+// IsTransient returns true if the error returned by Clone may go away by retrying, such as network errors, server errors
+// or too many requests. The other errors, e.g. not found, unauthorized, unknown ref, or a cancellation, are permanent.
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	for _, e := range transientErrors {
+		if errors.Is(err, e) {
+			return true
+		}
+	}
+
+	if ue := (*plumbing.UnexpectedError)(nil); errors.As(err, &ue) {
+		err = ue.Err
+	}
+
+	if he := (*githttp.Err)(nil); errors.As(err, &he) {
+		code := he.StatusCode()
+
+		return code >= http.StatusInternalServerError || code == http.StatusTooManyRequests || code == http.StatusRequestTimeout
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr)
+}
+
+var _ Cloner = (*RetryCloner)(nil)
+
+// RetryCloner retries cloning a repository with exponential backoff when the error is transient.
+type RetryCloner struct {
+	upstream Cloner
+
+	maxRetries     int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+// Clone clones a repository.
+func (c *RetryCloner) Clone(ctx context.Context, url, ref string) (string, *git.Repository, error) {
+	backoff := c.initialBackoff
+
+	for attempt := 0; ; attempt++ {
+		dir, r, err := c.upstream.Clone(ctx, url, ref)
+		if err == nil || attempt >= c.maxRetries || !IsTransient(err) {
+			return dir, r, err
+		}
+
+		t := time.NewTimer(backoff)
+
+		select {
+		case <-ctx.Done():
+			t.Stop()
+
+			return "", nil, ctx.Err()
+
+		case <-t.C:
+		}
+
+		backoff = min(backoff*2, c.maxBackoff)
+	}
+}
+
+// NewRetryCloner returns a new cloner that retries on transient errors.
+func NewRetryCloner(upstream Cloner, opts ...RetryClonerOption) *RetryCloner {
+	c := &RetryCloner{
+		upstream:       upstream,
+		maxRetries:     defaultMaxRetries,
+		initialBackoff: defaultInitialBackoff,
+		maxBackoff:     defaultMaxBackoff,
+	}
+
+	for _, o := range opts {
+		o.applyRetryClonerOption(c)
+	}
+
+	return c
+}
+
+// RetryClonerOption is an option to configure RetryCloner.
+type RetryClonerOption interface {
+	applyRetryClonerOption(c *RetryCloner)
+}
+
+type retryClonerOptionFunc func(c *RetryCloner)
+
+func (f retryClonerOptionFunc) applyRetryClonerOption(c *RetryCloner) {
+	f(c)
+}
+
+// WithMaxRetries sets the maximum number of retries after the first attempt. Zero disables retrying.
+func WithMaxRetries(n int) RetryClonerOption {
+	return retryClonerOptionFunc(func(c *RetryCloner) {
+		c.maxRetries = max(n, 0)
+	})
+}
+
+// WithBackoff sets the initial and the maximum delays between the attempts. The delay doubles after each attempt.
+func WithBackoff(initial, maxBackoff time.Duration) RetryClonerOption {
+	return retryClonerOptionFunc(func(c *RetryCloner) {
+		c.initialBackoff = initial
+		c.maxBackoff = max(initial, maxBackoff)
+	})
+}
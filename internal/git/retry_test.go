@@ -0,0 +1,206 @@
+package git_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"path/filepath"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/vanityrender/internal/git"
+)
+
+func TestIsTransient(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		err      error
+		expected bool
+	}{
+		{
+			scenario: "no error",
+		},
+		{
+			scenario: "repository not found",
+			err:      fmt.Errorf("could not clone repository: %w", transport.ErrRepositoryNotFound),
+		},
+		{
+			scenario: "authentication required",
+			err:      fmt.Errorf("could not clone repository: %w", transport.ErrAuthenticationRequired),
+		},
+		{
+			scenario: "authorization failed",
+			err:      fmt.Errorf("could not clone repository: %w", transport.ErrAuthorizationFailed),
+		},
+		{
+			scenario: "reference not found",
+			err:      fmt.Errorf("could not resolve ref %q: %w", "unknown", plumbing.ErrReferenceNotFound),
+		},
+		{
+			scenario: "canceled",
+			err:      fmt.Errorf("could not clone repository: %w", context.Canceled),
+		},
+		{
+			scenario: "deadline exceeded",
+			err:      fmt.Errorf("could not clone repository: %w", context.DeadlineExceeded),
+		},
+		{
+			scenario: "connection reset",
+			err:      fmt.Errorf("could not clone repository: %w", &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}),
+			expected: true,
+		},
+		{
+			scenario: "unexpected eof",
+			err:      fmt.Errorf("could not clone repository: %w", io.ErrUnexpectedEOF),
+			expected: true,
+		},
+		{
+			scenario: "network error",
+			err:      fmt.Errorf("could not clone repository: %w", &net.DNSError{Err: "no such host", Name: "github.com", IsTemporary: true}),
+			expected: true,
+		},
+		{
+			scenario: "unknown error",
+			err:      fmt.Errorf("could not checkout revision: %w", errors.New("invalid checksum")),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, git.IsTransient(tc.err))
+		})
+	}
+}
+
+func TestRetryCloner_Clone_TransientError(t *testing.T) {
+	t.Parallel()
+
+	root, name := mockServedRepository(t)
+	url := mockGitServer(t, root, failFirst(2, http.StatusServiceUnavailable)) + "/" + name
+
+	upstream, calls := countCalls(git.ClonerFunc(git.Clone))
+	c := git.NewRetryCloner(upstream, git.WithBackoff(time.Millisecond, 5*time.Millisecond))
+
+	dir, r, err := c.Clone(t.Context(), url, "")
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, dir)
+	assert.NotNil(t, r)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestRetryCloner_Clone_TooManyTransientErrors(t *testing.T) {
+	t.Parallel()
+
+	root, name := mockServedRepository(t)
+	url := mockGitServer(t, root, failFirst(100, http.StatusBadGateway)) + "/" + name
+
+	upstream, calls := countCalls(git.ClonerFunc(git.Clone))
+	c := git.NewRetryCloner(upstream, git.WithMaxRetries(2), git.WithBackoff(time.Millisecond, 5*time.Millisecond))
+
+	_, _, err := c.Clone(t.Context(), url, "")
+
+	assert.ErrorContains(t, err, "could not clone repository: unexpected client error: unexpected requesting")
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestRetryCloner_Clone_PermanentError(t *testing.T) {
+	t.Parallel()
+
+	root, name := mockServedRepository(t)
+
+	testCases := []struct {
+		scenario      string
+		url           string
+		ref           string
+		expectedError error
+	}{
+		{
+			scenario:      "not found",
+			url:           mockGitServer(t, root) + "/not-found",
+			expectedError: transport.ErrRepositoryNotFound,
+		},
+		{
+			scenario:      "authentication required",
+			url:           mockGitServer(t, root, failFirst(100, http.StatusUnauthorized)) + "/" + name,
+			expectedError: transport.ErrAuthenticationRequired,
+		},
+		{
+			scenario:      "authorization failed",
+			url:           mockGitServer(t, root, failFirst(100, http.StatusForbidden)) + "/" + name,
+			expectedError: transport.ErrAuthorizationFailed,
+		},
+		{
+			scenario:      "unknown ref",
+			url:           mockGitServer(t, root) + "/" + name,
+			ref:           "unknown",
+			expectedError: plumbing.ErrReferenceNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			upstream, calls := countCalls(git.ClonerFunc(git.Clone))
+			c := git.NewRetryCloner(upstream, git.WithBackoff(time.Millisecond, 5*time.Millisecond))
+
+			_, _, err := c.Clone(t.Context(), tc.url, tc.ref)
+
+			assert.ErrorIs(t, err, tc.expectedError)
+			assert.Equal(t, int32(1), calls.Load())
+		})
+	}
+}
+
+func TestRetryCloner_Clone_CanceledWhileWaiting(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+
+	upstream, calls := countCalls(git.ClonerFunc(func(context.Context, string, string) (string, *gogit.Repository, error) {
+		cancel()
+
+		return "", nil, syscall.ECONNRESET
+	}))
+
+	c := git.NewRetryCloner(upstream, git.WithBackoff(time.Hour, time.Hour))
+
+	_, _, err := c.Clone(ctx, "https://github.com/org/repository", "")
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func mockServedRepository(t *testing.T) (string, string) {
+	t.Helper()
+
+	dir := mockRepository(tagRepositoryHead("v0.1.0"))(t)
+
+	return filepath.Dir(dir), filepath.Base(dir)
+}
+
+func countCalls(c git.Cloner) (git.Cloner, *atomic.Int32) {
+	var calls atomic.Int32
+
+	return git.ClonerFunc(func(ctx context.Context, url, ref string) (string, *gogit.Repository, error) {
+		calls.Add(1)
+
+		return c.Clone(ctx, url, ref)
+	}), &calls
+}
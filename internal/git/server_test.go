@@ -0,0 +1,155 @@
+package git_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/go-git/go-git/v5/plumbing/format/pktline"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp/capability"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/server"
+)
+
+// mockGitServer starts a git smart HTTP server that serves the bare repositories in the given directory. The
+// middlewares are applied in order and can be used to simulate a flaky server.
+func mockGitServer(t *testing.T, root string, middlewares ...func(http.Handler) http.Handler) string {
+	t.Helper()
+
+	srv := server.NewServer(server.NewFilesystemLoader(osfs.New(root)))
+
+	var h http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/info/refs"):
+			serveAdvertisedReferences(t, srv, w, r)
+
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/git-upload-pack"):
+			serveUploadPack(t, srv, w, r)
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+
+	s := httptest.NewServer(h)
+
+	t.Cleanup(s.Close)
+
+	return s.URL
+}
+
+func uploadPackSession(srv transport.Transport, w http.ResponseWriter, path string) transport.UploadPackSession {
+	ep, err := transport.NewEndpoint(path)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return nil
+	}
+
+	sess, err := srv.NewUploadPackSession(ep, nil)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+
+		return nil
+	}
+
+	return sess
+}
+
+func serveAdvertisedReferences(t *testing.T, srv transport.Transport, w http.ResponseWriter, r *http.Request) {
+	t.Helper()
+
+	sess := uploadPackSession(srv, w, strings.TrimSuffix(r.URL.Path, "/info/refs"))
+	if sess == nil {
+		return
+	}
+
+	ar, err := sess.AdvertisedReferencesContext(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	ar.Prefix = [][]byte{[]byte("# service=git-upload-pack"), pktline.Flush}
+
+	w.Header().Set("Content-Type", "application/x-git-upload-pack-advertisement")
+
+	if err := ar.Encode(w); err != nil {
+		t.Logf("could not encode advertised references: %s", err)
+	}
+}
+
+func serveUploadPack(t *testing.T, srv transport.Transport, w http.ResponseWriter, r *http.Request) {
+	t.Helper()
+
+	sess := uploadPackSession(srv, w, strings.TrimSuffix(r.URL.Path, "/git-upload-pack"))
+	if sess == nil {
+		return
+	}
+
+	req := packp.NewUploadPackRequest()
+	if err := req.Decode(r.Body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	// The server does not support shallow fetches, so it sends the full history with an empty shallow update.
+	shallow := !req.Depth.IsZero()
+
+	req.Depth = packp.DepthCommits(0)
+	req.Capabilities.Delete(capability.Shallow)
+
+	resp, err := sess.UploadPack(r.Context(), req)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-git-upload-pack-result")
+
+	if shallow {
+		if err := (&packp.ShallowUpdate{}).Encode(w); err != nil {
+			t.Logf("could not encode shallow update: %s", err)
+		}
+	}
+
+	if err := resp.Encode(w); err != nil {
+		t.Logf("could not encode upload pack response: %s", err)
+	}
+}
+
+// failFirst responds with the status code to the first n requests.
+func failFirst(n int, status int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		var (
+			mu    sync.Mutex
+			count int
+		)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			count++
+			fail := count <= n
+			mu.Unlock()
+
+			if fail {
+				w.WriteHeader(status)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -2,17 +2,22 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/fatih/color"
 
 	"go.nhat.io/vanityrender/internal/module"
+	"go.nhat.io/vanityrender/internal/repoinfo"
 	"go.nhat.io/vanityrender/internal/site"
+	"go.nhat.io/vanityrender/internal/vcs"
 )
 
 const (
@@ -35,20 +40,20 @@ var _ site.Hydrator = (*Hydrator)(nil)
 type Hydrator struct {
 	finder module.Finder
 
-	numWorkers int
-	output     io.Writer
+	numWorkers   int
+	cloneTimeout time.Duration
+	readmes      bool
+	output       io.Writer
 }
 
-// Hydrate hydrates the configuration.
-func (h *Hydrator) Hydrate(s *site.Site) error {
+// Hydrate hydrates the configuration. A failed repository does not stop the others, all the failures are returned as
+// site.RepositoryError, joined with errors.Join.
+func (h *Hydrator) Hydrate(ctx context.Context, s *site.Site) error {
 	ch := make(chan *site.Repository, h.numWorkers*2)
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
 	wg := sync.WaitGroup{}
 	errMu := sync.Mutex{}
-	err := (error)(nil)
+	failures := make([]*site.RepositoryError, 0)
 
 	wg.Add(h.numWorkers)
 
@@ -66,12 +71,10 @@ func (h *Hydrator) Hydrate(s *site.Site) error {
 						return
 					}
 
-					if hErr := h.hydrateRepository(r); hErr != nil {
+					if err := h.hydrateRepository(ctx, s.Hostname, r); err != nil {
 						errMu.Lock()
-						err = hErr
+						failures = append(failures, site.NewRepositoryError(*r, err))
 						errMu.Unlock()
-
-						cancel()
 					}
 				}
 			}
@@ -82,16 +85,35 @@ func (h *Hydrator) Hydrate(s *site.Site) error {
 		defer close(ch)
 
 		for i := range s.Repositories {
-			ch <- &s.Repositories[i]
+			select {
+			case <-ctx.Done():
+				return
+
+			case ch <- &s.Repositories[i]:
+			}
 		}
 	}()
 
 	wg.Wait()
 
-	return err
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].Path < failures[j].Path
+	})
+
+	errs := make([]error, 0, len(failures)+1)
+
+	for _, f := range failures {
+		errs = append(errs, f)
+	}
+
+	return errors.Join(append(errs, ctx.Err())...)
 }
 
-func (h *Hydrator) hydrateRepository(r *site.Repository) error {
+func (h *Hydrator) hydrateRepository(ctx context.Context, host string, r *site.Repository) error {
+	if !vcs.IsGit(r.VCS) {
+		return nil
+	}
+
 	repoURL := repositoryURL(r.RepositoryURL)
 
 	if !strings.Contains(repoURL, gitHubDomain) {
@@ -100,7 +122,23 @@ func (h *Hydrator) hydrateRepository(r *site.Repository) error {
 
 	_, _ = fmt.Fprintln(h.output, color.HiBlueString("Read"), ":", repoURL) //nolint: errcheck
 
-	pathVersions, err := h.finder.Find(repoURL, r.Ref)
+	if timeout := h.timeout(r); timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	src := module.Source{
+		Repository: repoURL,
+		Ref:        r.Ref,
+		ImportPath: path.Join(host, r.Path),
+		Submodules: r.Submodules,
+		Filter:     module.PathFilter{Include: r.Include, Exclude: r.Exclude},
+		AllTags:    r.AllTags,
+	}
+
+	pathVersions, err := h.finder.Find(ctx, src)
 	if err != nil {
 		return err // nolint: wrapcheck
 	}
@@ -108,13 +146,15 @@ func (h *Hydrator) hydrateRepository(r *site.Repository) error {
 	r.RepositoryURL = repoURL
 	r.RepositoryName = repositoryURLSanitizer.Replace(repoURL)
 
+	readmes := h.findReadmes(ctx, src, pathVersions)
+
 	modules := make([]site.Module, 0, len(pathVersions))
 	latestVersion := module.Version{}
 
-	for path, version := range pathVersions {
+	for p, version := range pathVersions {
 		modulePath := r.Path
-		if string(path) != "." {
-			modulePath = filepath.Join(r.Path, string(path))
+		if string(p) != "." {
+			modulePath = filepath.Join(r.Path, string(p))
 		}
 
 		_, _ = fmt.Fprintln(h.output, color.HiYellowString("Find Module"), ":", modulePath, version) //nolint: errcheck
@@ -127,9 +167,11 @@ func (h *Hydrator) hydrateRepository(r *site.Repository) error {
 			HomeURL:       r.RepositoryURL,
 			DirectoryURL:  fmt.Sprintf("%s/tree/master{/dir}", r.RepositoryURL),
 			FileURL:       fmt.Sprintf("%s/blob/master{/dir}/{file}#L{line}", r.RepositoryURL),
+			Version:       versionString(version),
+			Readme:        h.readmeHTML(r, readmes[p]),
 		})
 
-		if path.IsRoot() && latestVersion.LessThan(version) {
+		if p.IsRoot() && latestVersion.LessThan(version) {
 			latestVersion = version
 		}
 	}
@@ -138,16 +180,118 @@ func (h *Hydrator) hydrateRepository(r *site.Repository) error {
 		return modules[i].Path < modules[j].Path
 	})
 
+	if err := markSuperseded(modules); err != nil {
+		return err
+	}
+
+	r.LatestPath = ""
+
 	if latestVersion.Major > 1 {
-		r.Path = module.PathWithVersion(r.Path, latestVersion)
+		r.LatestPath = module.PathWithVersion(r.Path, latestVersion)
 	}
 
-	r.LatestVersion = latestVersion.String()
+	r.LatestVersion = versionString(latestVersion)
 	r.Modules = modules
 
 	return nil
 }
 
+// versionString returns the version of a module, or an empty string if the module is not tagged yet, e.g. a submodule
+// that is added on a branch. The finders report such modules at v0.0.0.
+func versionString(v module.Version) string {
+	if v == (module.Version{}) {
+		return ""
+	}
+
+	return v.String()
+}
+
+// markSuperseded marks the modules that have a newer major version, e.g. x/v2 is superseded by x/v3.
+func markSuperseded(modules []site.Module) error {
+	latest := make(map[string]module.Version, len(modules))
+	latestPath := make(map[string]string, len(modules))
+
+	for _, m := range modules {
+		base := module.PathWithoutVersion(m.Path)
+		// The untagged modules have no version.
+		v := module.Version{}
+
+		if len(m.Version) > 0 {
+			var err error
+
+			if v, err = module.ParseVersion(m.Version); err != nil {
+				return fmt.Errorf("module %s: %w", m.Path, err)
+			}
+		}
+
+		if cur, ok := latest[base]; !ok || cur.Major < v.Major {
+			latest[base] = v
+			latestPath[base] = m.Path
+		}
+	}
+
+	for i, m := range modules {
+		if p := latestPath[module.PathWithoutVersion(m.Path)]; p != m.Path {
+			modules[i].Superseded = p
+		}
+	}
+
+	return nil
+}
+
+// findReadmes returns the README files of the modules, when they are enabled and the finder reads them. The README files
+// are optional, so an error is reported but does not fail the repository.
+func (h *Hydrator) findReadmes(ctx context.Context, src module.Source, pathVersions map[module.Path]module.Version) map[module.Path]module.Readme {
+	finder, ok := h.finder.(module.ReadmeFinder)
+	if !h.readmes || !ok {
+		return nil
+	}
+
+	paths := make([]module.Path, 0, len(pathVersions))
+
+	for p := range pathVersions {
+		paths = append(paths, p)
+	}
+
+	readmes, err := finder.FindReadmes(ctx, src, paths)
+	if err != nil {
+		_, _ = fmt.Fprintln(h.output, color.HiRedString("Readme Error"), ":", src.Repository, err) //nolint: errcheck
+
+		return nil
+	}
+
+	return readmes
+}
+
+// readmeHTML renders the README file of a module, with the relative links pointing to the files on GitHub.
+func (h *Hydrator) readmeHTML(r *site.Repository, readme module.Readme) string {
+	if len(readme.Content) == 0 {
+		return ""
+	}
+
+	ref := r.Ref
+	if len(ref) == 0 {
+		ref = "HEAD"
+	}
+
+	result, err := repoinfo.ReadmeHTML(readme.Name, readme.Content, fmt.Sprintf("%s/blob/%s", r.RepositoryURL, ref), readme.Dir)
+	if err != nil {
+		_, _ = fmt.Fprintln(h.output, color.HiRedString("Readme Error"), ":", r.RepositoryURL+"/"+path.Join(readme.Dir, readme.Name), err) //nolint: errcheck
+
+		return ""
+	}
+
+	return result
+}
+
+func (h *Hydrator) timeout(r *site.Repository) time.Duration {
+	if r.CloneTimeout > 0 {
+		return r.CloneTimeout
+	}
+
+	return h.cloneTimeout
+}
+
 // NewHydrator initiates a new config.Hydrator.
 func NewHydrator(finder module.Finder, opts ...HydratorOption) *Hydrator {
 	h := &Hydrator{
@@ -186,3 +330,17 @@ func WithOutput(w io.Writer) HydratorOption {
 		r.output = w
 	})
 }
+
+// WithReadmes renders the README files of the modules, when the finder is a module.ReadmeFinder.
+func WithReadmes() HydratorOption {
+	return hydratorOptionFunc(func(r *Hydrator) {
+		r.readmes = true
+	})
+}
+
+// WithCloneTimeout sets the default timeout for finding the modules of a repository. Zero means no timeout.
+func WithCloneTimeout(d time.Duration) HydratorOption {
+	return hydratorOptionFunc(func(r *Hydrator) {
+		r.cloneTimeout = d
+	})
+}
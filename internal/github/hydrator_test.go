@@ -1,10 +1,14 @@
 package github_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"go.nhat.io/vanityrender/internal/github"
 	"go.nhat.io/vanityrender/internal/module"
@@ -15,11 +19,11 @@ func TestHydrator_Hydrate(t *testing.T) {
 	t.Parallel()
 
 	pathVersions := map[module.Path]module.Version{
-		".":          module.NewVersionFromString("v1.0.0"),
-		"v2":         module.NewVersionFromString("v2.10.0"),
-		"contrib":    module.NewVersionFromString("v0.2.0"),
-		"contrib/v2": module.NewVersionFromString("v2.0.0"),
-		"test":       module.NewVersionFromString("v0.2.0"),
+		".":          module.NewVersion(1, 0, 0),
+		"v2":         module.NewVersion(2, 10, 0),
+		"contrib":    module.NewVersion(0, 2, 0),
+		"contrib/v2": module.NewVersion(2, 0, 0),
+		"test":       module.NewVersion(0, 2, 0),
 	}
 
 	expectedModules := []site.Module{
@@ -31,6 +35,8 @@ func TestHydrator_Hydrate(t *testing.T) {
 			HomeURL:       "https://github.com/org/repository",
 			DirectoryURL:  "https://github.com/org/repository/tree/master{/dir}",
 			FileURL:       "https://github.com/org/repository/blob/master{/dir}/{file}#L{line}",
+			Version:       "v1.0.0",
+			Superseded:    "repository/v2",
 		},
 		{
 			Path:          "repository/contrib",
@@ -40,6 +46,8 @@ func TestHydrator_Hydrate(t *testing.T) {
 			HomeURL:       "https://github.com/org/repository",
 			DirectoryURL:  "https://github.com/org/repository/tree/master{/dir}",
 			FileURL:       "https://github.com/org/repository/blob/master{/dir}/{file}#L{line}",
+			Version:       "v0.2.0",
+			Superseded:    "repository/contrib/v2",
 		},
 		{
 			Path:          "repository/contrib/v2",
@@ -49,6 +57,7 @@ func TestHydrator_Hydrate(t *testing.T) {
 			HomeURL:       "https://github.com/org/repository",
 			DirectoryURL:  "https://github.com/org/repository/tree/master{/dir}",
 			FileURL:       "https://github.com/org/repository/blob/master{/dir}/{file}#L{line}",
+			Version:       "v2.0.0",
 		},
 		{
 			Path:          "repository/test",
@@ -58,6 +67,7 @@ func TestHydrator_Hydrate(t *testing.T) {
 			HomeURL:       "https://github.com/org/repository",
 			DirectoryURL:  "https://github.com/org/repository/tree/master{/dir}",
 			FileURL:       "https://github.com/org/repository/blob/master{/dir}/{file}#L{line}",
+			Version:       "v0.2.0",
 		},
 		{
 			Path:          "repository/v2",
@@ -67,6 +77,7 @@ func TestHydrator_Hydrate(t *testing.T) {
 			HomeURL:       "https://github.com/org/repository",
 			DirectoryURL:  "https://github.com/org/repository/tree/master{/dir}",
 			FileURL:       "https://github.com/org/repository/blob/master{/dir}/{file}#L{line}",
+			Version:       "v2.10.0",
 		},
 	}
 
@@ -90,20 +101,37 @@ func TestHydrator_Hydrate(t *testing.T) {
 				}},
 			},
 		},
+		{
+			scenario: "ignore non-git",
+			site: site.Site{
+				Repositories: []site.Repository{{
+					RepositoryURL: "https://github.com/org/repository",
+					VCS:           "mod",
+				}},
+			},
+			expectedResult: site.Site{
+				Repositories: []site.Repository{{
+					RepositoryURL: "https://github.com/org/repository",
+					VCS:           "mod",
+				}},
+			},
+		},
 		{
 			scenario:     "error",
 			moduleFinder: mockModuleFinderError(errors.New("find error")),
 			site: site.Site{
 				Repositories: []site.Repository{{
 					RepositoryURL: "https://github.com/org/repository",
+					Path:          "repository",
 				}},
 			},
 			expectedResult: site.Site{
 				Repositories: []site.Repository{{
 					RepositoryURL: "https://github.com/org/repository",
+					Path:          "repository",
 				}},
 			},
-			expectedError: "find error",
+			expectedError: "repository: find error",
 		},
 		{
 			scenario:     "success - http",
@@ -118,9 +146,10 @@ func TestHydrator_Hydrate(t *testing.T) {
 				Repositories: []site.Repository{{
 					RepositoryURL:  "https://github.com/org/repository",
 					RepositoryName: "github.com/org/repository",
-					Path:           "repository/v2",
+					Path:           "repository",
 					Modules:        expectedModules,
 					LatestVersion:  "v2.10.0",
+					LatestPath:     "repository/v2",
 				}},
 			},
 		},
@@ -137,9 +166,10 @@ func TestHydrator_Hydrate(t *testing.T) {
 				Repositories: []site.Repository{{
 					RepositoryURL:  "https://github.com/org/repository",
 					RepositoryName: "github.com/org/repository",
-					Path:           "repository/v2",
+					Path:           "repository",
 					Modules:        expectedModules,
 					LatestVersion:  "v2.10.0",
+					LatestPath:     "repository/v2",
 				}},
 			},
 		},
@@ -156,9 +186,10 @@ func TestHydrator_Hydrate(t *testing.T) {
 				Repositories: []site.Repository{{
 					RepositoryURL:  "https://github.com/org/repository",
 					RepositoryName: "github.com/org/repository",
-					Path:           "repository/v2",
+					Path:           "repository",
 					Modules:        expectedModules,
 					LatestVersion:  "v2.10.0",
+					LatestPath:     "repository/v2",
 				}},
 			},
 		},
@@ -176,9 +207,10 @@ func TestHydrator_Hydrate(t *testing.T) {
 				Repositories: []site.Repository{{
 					RepositoryURL:  "https://github.com/org/repository",
 					RepositoryName: "github.com/org/repository",
-					Path:           "repository/v2",
+					Path:           "repository",
 					Modules:        expectedModules,
 					LatestVersion:  "v2.10.0",
+					LatestPath:     "repository/v2",
 				}},
 			},
 		},
@@ -188,7 +220,7 @@ func TestHydrator_Hydrate(t *testing.T) {
 		t.Run(tc.scenario, func(t *testing.T) {
 			t.Parallel()
 
-			err := github.NewHydrator(tc.moduleFinder).Hydrate(&tc.site)
+			err := github.NewHydrator(tc.moduleFinder).Hydrate(t.Context(), &tc.site)
 
 			assert.Equal(t, tc.expectedResult, tc.site)
 
@@ -201,10 +233,258 @@ func TestHydrator_Hydrate(t *testing.T) {
 	}
 }
 
+func TestHydrator_Hydrate_Canceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+
+	finder := moduleFinderFunc(func(string, string) (map[module.Path]module.Version, error) {
+		cancel()
+
+		return map[module.Path]module.Version{}, nil
+	})
+
+	s := site.Site{
+		Repositories: make([]site.Repository, 20),
+	}
+
+	for i := range s.Repositories {
+		s.Repositories[i].RepositoryURL = fmt.Sprintf("https://github.com/org/repository-%d", i)
+	}
+
+	err := github.NewHydrator(finder).Hydrate(ctx, &s)
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestHydrator_Hydrate_Untagged(t *testing.T) {
+	t.Parallel()
+
+	finder := mockModuleFinder(map[module.Path]module.Version{
+		".":   module.NewVersion(0, 0, 0),
+		"sub": module.NewVersion(0, 0, 0),
+		"v2":  module.NewVersion(2, 0, 0),
+	})
+
+	s := site.Site{
+		Repositories: []site.Repository{{Path: "repository", RepositoryURL: "https://github.com/org/repository"}},
+	}
+
+	err := github.NewHydrator(finder).Hydrate(t.Context(), &s)
+	require.NoError(t, err)
+
+	r := s.Repositories[0]
+
+	// The untagged modules have no version instead of v0.0.0.
+	assert.Equal(t, "v2.0.0", r.LatestVersion)
+	require.Len(t, r.Modules, 3)
+	assert.Empty(t, r.Modules[0].Version)
+	assert.Equal(t, "repository/v2", r.Modules[0].Superseded)
+	assert.Empty(t, r.Modules[1].Version)
+	assert.Equal(t, "v2.0.0", r.Modules[2].Version)
+
+	s = site.Site{
+		Repositories: []site.Repository{{Path: "untagged", RepositoryURL: "https://github.com/org/untagged"}},
+	}
+
+	err = github.NewHydrator(mockModuleFinder(map[module.Path]module.Version{".": module.NewVersion(0, 0, 0)})).Hydrate(t.Context(), &s)
+	require.NoError(t, err)
+
+	assert.Empty(t, s.Repositories[0].LatestVersion)
+	assert.Empty(t, s.Repositories[0].Modules[0].Version)
+}
+
+func TestHydrator_Hydrate_AllErrors(t *testing.T) {
+	t.Parallel()
+
+	finder := moduleFinderFunc(func(loc, _ string) (map[module.Path]module.Version, error) {
+		if loc == "https://github.com/org/repository" {
+			return map[module.Path]module.Version{".": module.NewVersion(0, 2, 0)}, nil
+		}
+
+		return nil, fmt.Errorf("could not clone %s", loc)
+	})
+
+	s := site.Site{
+		Repositories: []site.Repository{
+			{Name: "Z", Path: "z", RepositoryURL: "https://github.com/org/z"},
+			{Name: "Repository", Path: "repository", RepositoryURL: "https://github.com/org/repository"},
+			{Name: "A", Path: "a", RepositoryURL: "https://github.com/org/a"},
+		},
+	}
+
+	err := github.NewHydrator(finder).Hydrate(t.Context(), &s)
+
+	expectedError := "a: could not clone https://github.com/org/a\nz: could not clone https://github.com/org/z"
+
+	assert.EqualError(t, err, expectedError)
+
+	failures := site.RepositoryErrors(err)
+
+	require.Len(t, failures, 2)
+	assert.Equal(t, "A", failures[0].Name)
+	assert.Equal(t, "Z", failures[1].Name)
+
+	assert.Empty(t, s.Repositories[0].LatestVersion)
+	assert.Equal(t, "v0.2.0", s.Repositories[1].LatestVersion)
+	assert.Empty(t, s.Repositories[2].LatestVersion)
+}
+
+func TestHydrator_Hydrate_CloneTimeout(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario     string
+		cloneTimeout time.Duration
+		repository   site.Repository
+		expectedErr  error
+	}{
+		{
+			scenario: "no timeout",
+			repository: site.Repository{
+				RepositoryURL: "https://github.com/org/repository",
+			},
+		},
+		{
+			scenario:     "default timeout",
+			cloneTimeout: time.Millisecond,
+			repository: site.Repository{
+				RepositoryURL: "https://github.com/org/repository",
+			},
+			expectedErr: context.DeadlineExceeded,
+		},
+		{
+			scenario:     "repository timeout",
+			cloneTimeout: time.Hour,
+			repository: site.Repository{
+				RepositoryURL: "https://github.com/org/repository",
+				CloneTimeout:  time.Millisecond,
+			},
+			expectedErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			finder := moduleFinderCtxFunc(func(ctx context.Context) (map[module.Path]module.Version, error) {
+				if _, ok := ctx.Deadline(); !ok {
+					return map[module.Path]module.Version{}, nil
+				}
+
+				<-ctx.Done()
+
+				return nil, ctx.Err()
+			})
+
+			s := site.Site{Repositories: []site.Repository{tc.repository}}
+
+			err := github.NewHydrator(finder, github.WithCloneTimeout(tc.cloneTimeout)).Hydrate(t.Context(), &s)
+
+			if tc.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestHydrator_Hydrate_Readmes(t *testing.T) {
+	t.Parallel()
+
+	pathVersions := map[module.Path]module.Version{
+		".":       module.NewVersion(1, 0, 0),
+		"contrib": module.NewVersion(0, 2, 0),
+	}
+
+	readmes := map[module.Path]module.Readme{
+		".":       {Dir: ".", Name: "README.md", Content: []byte("# Repository\n\nSee [contrib](contrib).\n")},
+		"contrib": {Dir: "contrib", Name: "README", Content: []byte("Contrib\n")},
+	}
+
+	testCases := []struct {
+		scenario        string
+		repository      site.Repository
+		opts            []github.HydratorOption
+		finderErr       error
+		expectedReadmes []string
+	}{
+		{
+			scenario:        "disabled",
+			repository:      site.Repository{RepositoryURL: "https://github.com/org/repository", Path: "repository"},
+			expectedReadmes: []string{"", ""},
+		},
+		{
+			scenario:   "enabled",
+			repository: site.Repository{RepositoryURL: "https://github.com/org/repository", Path: "repository"},
+			opts:       []github.HydratorOption{github.WithReadmes()},
+			expectedReadmes: []string{
+				"<h1 id=\"repository\">Repository</h1>\n<p>See <a href=\"https://github.com/org/repository/blob/HEAD/contrib\" rel=\"nofollow\">contrib</a>.</p>\n",
+				"<pre>Contrib\n</pre>",
+			},
+		},
+		{
+			scenario:   "enabled with ref",
+			repository: site.Repository{RepositoryURL: "https://github.com/org/repository", Path: "repository", Ref: "v1"},
+			opts:       []github.HydratorOption{github.WithReadmes()},
+			expectedReadmes: []string{
+				"<h1 id=\"repository\">Repository</h1>\n<p>See <a href=\"https://github.com/org/repository/blob/v1/contrib\" rel=\"nofollow\">contrib</a>.</p>\n",
+				"<pre>Contrib\n</pre>",
+			},
+		},
+		{
+			scenario:        "readmes could not be read",
+			repository:      site.Repository{RepositoryURL: "https://github.com/org/repository", Path: "repository"},
+			opts:            []github.HydratorOption{github.WithReadmes()},
+			finderErr:       errors.New("read error"),
+			expectedReadmes: []string{"", ""},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			s := site.Site{Repositories: []site.Repository{tc.repository}}
+			f := &readmeFinder{moduleFinderFunc: mockModuleFinder(pathVersions), readmes: readmes, err: tc.finderErr}
+
+			err := github.NewHydrator(f, tc.opts...).Hydrate(t.Context(), &s)
+			require.NoError(t, err)
+
+			actual := make([]string, 0, len(s.Repositories[0].Modules))
+
+			for _, m := range s.Repositories[0].Modules {
+				actual = append(actual, m.Readme)
+			}
+
+			assert.Equal(t, tc.expectedReadmes, actual)
+		})
+	}
+}
+
+type readmeFinder struct {
+	moduleFinderFunc
+
+	readmes map[module.Path]module.Readme
+	err     error
+}
+
+func (f *readmeFinder) FindReadmes(context.Context, module.Source, []module.Path) (map[module.Path]module.Readme, error) {
+	return f.readmes, f.err
+}
+
+type moduleFinderCtxFunc func(ctx context.Context) (map[module.Path]module.Version, error)
+
+func (f moduleFinderCtxFunc) Find(ctx context.Context, _ module.Source) (map[module.Path]module.Version, error) {
+	return f(ctx)
+}
+
 type moduleFinderFunc func(loc, ref string) (map[module.Path]module.Version, error)
 
-func (f moduleFinderFunc) Find(loc, ref string) (map[module.Path]module.Version, error) {
-	return f(loc, ref)
+func (f moduleFinderFunc) Find(_ context.Context, src module.Source) (map[module.Path]module.Version, error) {
+	return f(src.Repository, src.Ref)
 }
 
 func mockModuleFinderError(err error) moduleFinderFunc {
@@ -0,0 +1,2 @@
+// Package goproxy provides functionalities to find modules using a Go module proxy and to write a static one.
+package goproxy
@@ -0,0 +1,293 @@
+package goproxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	xmodule "golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+
+	xerrors "go.nhat.io/vanityrender/internal/errors"
+	"go.nhat.io/vanityrender/internal/module"
+)
+
+const (
+	// ErrMissingImportPath indicates that the source does not have an import path.
+	ErrMissingImportPath = xerrors.Error("missing import path")
+	// ErrModulePathMismatch indicates that the go.mod file served by the proxy declares another module path.
+	ErrModulePathMismatch = xerrors.Error("module path mismatch")
+	// ErrDirectNotSupported indicates that the module must be fetched directly but there is no fallback finder.
+	ErrDirectNotSupported = xerrors.Error("direct module lookup is not supported")
+)
+
+// maxMajorGap is the number of consecutive major versions that may be missing before the probing stops, so a module that
+// went from v2 to v4 still has its v4 found.
+const maxMajorGap = 2
+
+var _ module.Finder = (*Finder)(nil)
+
+// Finder finds modules by querying a Go module proxy, so the result is exactly what `go get` users see. It queries the
+// root module and the known submodules of the source, for every major version until more than maxMajorGap consecutive
+// ones are not found. A source without a root module is found as long as one of its submodules is.
+//
+// The modules that match the GONOPROXY patterns, or that are resolved to "direct" by GOPROXY, are found by the fallback
+// finder.
+type Finder struct {
+	client   *http.Client
+	proxies  []proxy
+	noProxy  string
+	fallback module.Finder
+}
+
+// Find finds the modules of the source.
+func (f *Finder) Find(ctx context.Context, src module.Source) (map[module.Path]module.Version, error) {
+	if len(src.ImportPath) == 0 {
+		return nil, ErrMissingImportPath
+	}
+
+	if xmodule.MatchPrefixPatterns(f.noProxy, src.ImportPath) {
+		return f.findDirect(ctx, src)
+	}
+
+	result, err := f.findModule(ctx, src.ImportPath, ".")
+	if err != nil {
+		if errors.Is(err, errDirect) {
+			return f.findDirect(ctx, src)
+		}
+
+		return nil, err
+	}
+
+	for _, sub := range src.Submodules {
+		sub = strings.Trim(sub, "/")
+
+		versions, err := f.findModule(ctx, path.Join(src.ImportPath, sub), module.Path(sub))
+		if err != nil {
+			if errors.Is(err, errDirect) {
+				return f.findDirect(ctx, src)
+			}
+
+			return nil, err
+		}
+
+		for p, v := range versions {
+			result[p] = v
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, src.ImportPath)
+	}
+
+	return result, nil
+}
+
+func (f *Finder) findDirect(ctx context.Context, src module.Source) (map[module.Path]module.Version, error) {
+	if f.fallback == nil {
+		return nil, fmt.Errorf("%w: %s", ErrDirectNotSupported, src.ImportPath)
+	}
+
+	return f.fallback.Find(ctx, src)
+}
+
+// findModule finds the latest version of every major version of the module. The major versions are probed one by one,
+// starting from v1 (which also covers v0), until more than maxMajorGap consecutive ones are not found. A module that is
+// not found at all has an empty result.
+func (f *Finder) findModule(ctx context.Context, importPath string, relPath module.Path) (map[module.Path]module.Version, error) {
+	result := make(map[module.Path]module.Version)
+
+	for major, missing := 1, 0; missing <= maxMajorGap; major++ {
+		modPath := importPath
+		key := relPath
+
+		if major > 1 {
+			modPath = module.PathWithVersion(importPath, module.NewVersion(major, 0, 0))
+			key = module.Path(module.PathWithVersion(relPath, module.NewVersion(major, 0, 0)))
+		}
+
+		version, err := f.latestVersion(ctx, modPath)
+		if errors.Is(err, ErrNotFound) {
+			missing++
+
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		missing = 0
+		result[key] = version
+	}
+
+	return result, nil
+}
+
+// latestVersion returns the latest release version of the module. If there is no release, the latest version known by
+// the proxy is used, in which case only its major version is kept.
+func (f *Finder) latestVersion(ctx context.Context, modPath string) (module.Version, error) {
+	escapedPath, err := xmodule.EscapePath(modPath)
+	if err != nil {
+		return module.Version{}, fmt.Errorf("invalid module path %q: %w", modPath, err)
+	}
+
+	data, err := get(ctx, f.client, f.proxies, escapedPath+"/@v/list")
+	if err != nil {
+		return module.Version{}, err
+	}
+
+	latest := ""
+
+	for _, v := range strings.Fields(string(data)) {
+		if !semver.IsValid(v) || semver.Prerelease(v) != "" || semver.Build(v) != "" {
+			continue
+		}
+
+		if semver.Compare(v, latest) > 0 {
+			latest = v
+		}
+	}
+
+	if len(latest) == 0 {
+		if latest, err = f.latestInfo(ctx, escapedPath); err != nil {
+			return module.Version{}, err
+		}
+	}
+
+	if err := f.verifyModFile(ctx, modPath, escapedPath, latest); err != nil {
+		return module.Version{}, err
+	}
+
+	if !module.VersionRegExp.MatchString(latest) {
+		latest = semver.Major(latest) + ".0.0"
+	}
+
+	return module.ParseVersion(latest) // nolint: wrapcheck
+}
+
+func (f *Finder) latestInfo(ctx context.Context, escapedPath string) (string, error) {
+	data, err := get(ctx, f.client, f.proxies, escapedPath+"/@latest")
+	if err != nil {
+		return "", err
+	}
+
+	var info struct {
+		Version string
+	}
+
+	if err := json.Unmarshal(data, &info); err != nil {
+		return "", fmt.Errorf("could not decode %s/@latest: %w", escapedPath, err)
+	}
+
+	if !semver.IsValid(info.Version) {
+		return "", fmt.Errorf("%w: %s/@latest has no valid version", ErrNotFound, escapedPath)
+	}
+
+	return info.Version, nil
+}
+
+func (f *Finder) verifyModFile(ctx context.Context, modPath, escapedPath, version string) error {
+	escapedVersion, err := xmodule.EscapeVersion(version)
+	if err != nil {
+		return fmt.Errorf("invalid version %q: %w", version, err)
+	}
+
+	file := escapedPath + "/@v/" + escapedVersion + ".mod"
+
+	data, err := get(ctx, f.client, f.proxies, file)
+	if err != nil {
+		return err
+	}
+
+	mf, err := modfile.ParseLax(file, data, nil)
+	if err != nil {
+		return &module.GoModError{File: file, Err: err}
+	}
+
+	if mf.Module == nil || mf.Module.Mod.Path != modPath {
+		return fmt.Errorf("%w: %s@%s", ErrModulePathMismatch, modPath, version)
+	}
+
+	return nil
+}
+
+// NewFinder returns a new module finder that uses the Go module proxies. By default, it uses DefaultProxy and has no
+// fallback finder.
+func NewFinder(opts ...FinderOption) *Finder {
+	f := &Finder{
+		client:  newHTTPClient(),
+		proxies: parseProxyList(DefaultProxy),
+	}
+
+	for _, o := range opts {
+		o.applyFinderOption(f)
+	}
+
+	return f
+}
+
+// FinderOption is an option to configure Finder.
+type FinderOption interface {
+	applyFinderOption(f *Finder)
+}
+
+type finderOptionFunc func(f *Finder)
+
+func (fn finderOptionFunc) applyFinderOption(f *Finder) {
+	fn(f)
+}
+
+// WithProxy sets the proxy list, in the GOPROXY format. An empty list means DefaultProxy.
+func WithProxy(list string) FinderOption {
+	return finderOptionFunc(func(f *Finder) {
+		if len(strings.TrimSpace(list)) == 0 {
+			list = DefaultProxy
+		}
+
+		f.proxies = parseProxyList(list)
+	})
+}
+
+// WithNoProxy sets the glob patterns of the module path prefixes that are not fetched from the proxies, in the GONOPROXY
+// format.
+func WithNoProxy(patterns string) FinderOption {
+	return finderOptionFunc(func(f *Finder) {
+		f.noProxy = patterns
+	})
+}
+
+// WithEnv configures the finder with the GOPROXY, GONOPROXY and GOPRIVATE environment variables, using the given lookup
+// function, e.g. os.Getenv. Like the go command, GONOPROXY defaults to GOPRIVATE. GOSUMDB and GONOSUMDB are not used,
+// the go.mod files served by the proxies are not verified against a checksum database.
+func WithEnv(getenv func(key string) string) FinderOption {
+	return finderOptionFunc(func(f *Finder) {
+		WithProxy(getenv("GOPROXY")).applyFinderOption(f)
+
+		noProxy := getenv("GONOPROXY")
+		if len(noProxy) == 0 {
+			noProxy = getenv("GOPRIVATE")
+		}
+
+		WithNoProxy(noProxy).applyFinderOption(f)
+	})
+}
+
+// WithFallback sets the finder that is used when a module must be fetched directly from its repository.
+func WithFallback(fallback module.Finder) FinderOption {
+	return finderOptionFunc(func(f *Finder) {
+		f.fallback = fallback
+	})
+}
+
+// WithHTTPClient sets the http client that is used to query the proxies.
+func WithHTTPClient(c *http.Client) FinderOption {
+	return finderOptionFunc(func(f *Finder) {
+		f.client = c
+	})
+}
@@ -0,0 +1,293 @@
+package goproxy_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/vanityrender/internal/goproxy"
+	"go.nhat.io/vanityrender/internal/module"
+)
+
+func TestFinder_Find(t *testing.T) {
+	t.Parallel()
+
+	dir := mockProxy(t)
+
+	testCases := []struct {
+		scenario       string
+		source         module.Source
+		expectedResult map[module.Path]module.Version
+		expectedError  string
+	}{
+		{
+			scenario:      "missing import path",
+			source:        module.Source{},
+			expectedError: "missing import path",
+		},
+		{
+			scenario:      "not found",
+			source:        module.Source{ImportPath: "example.com/unknown"},
+			expectedError: "not found: example.com/unknown",
+		},
+		{
+			scenario: "root module only",
+			source:   module.Source{ImportPath: "example.com/v1only"},
+			expectedResult: map[module.Path]module.Version{
+				".": module.NewVersion(1, 2, 0),
+			},
+		},
+		{
+			scenario: "pseudo version only",
+			source:   module.Source{ImportPath: "example.com/pseudo"},
+			expectedResult: map[module.Path]module.Version{
+				".": module.NewVersion(0, 0, 0),
+			},
+		},
+		{
+			scenario: "major versions and submodules",
+			source: module.Source{
+				ImportPath: "example.com/lib",
+				Submodules: []string{"contrib", "unknown"},
+			},
+			expectedResult: map[module.Path]module.Version{
+				".":          module.NewVersion(1, 1, 0),
+				"v2":         module.NewVersion(2, 3, 0),
+				"contrib":    module.NewVersion(0, 4, 0),
+				"contrib/v2": module.NewVersion(2, 0, 1),
+			},
+		},
+		{
+			scenario: "v2 only",
+			source:   module.Source{ImportPath: "example.com/v2only"},
+			expectedResult: map[module.Path]module.Version{
+				"v2": module.NewVersion(2, 0, 0),
+			},
+		},
+		{
+			scenario: "missing major version",
+			source:   module.Source{ImportPath: "example.com/gap"},
+			expectedResult: map[module.Path]module.Version{
+				".":  module.NewVersion(1, 0, 0),
+				"v2": module.NewVersion(2, 1, 0),
+				"v4": module.NewVersion(4, 0, 0),
+			},
+		},
+		{
+			scenario: "submodules only",
+			source: module.Source{
+				ImportPath: "example.com/noroot",
+				Submodules: []string{"sub"},
+			},
+			expectedResult: map[module.Path]module.Version{
+				"sub": module.NewVersion(0, 2, 0),
+			},
+		},
+		{
+			scenario: "escaped path",
+			source:   module.Source{ImportPath: "example.com/Upper"},
+			expectedResult: map[module.Path]module.Version{
+				".": module.NewVersion(0, 1, 0),
+			},
+		},
+		{
+			scenario:      "module path mismatch",
+			source:        module.Source{ImportPath: "example.com/mismatch"},
+			expectedError: "module path mismatch: example.com/mismatch@v1.0.0",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			f := goproxy.NewFinder(goproxy.WithProxy("file://" + dir))
+
+			actual, err := f.Find(t.Context(), tc.source)
+
+			assert.Equal(t, tc.expectedResult, actual)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestFinder_Find_ProxyList(t *testing.T) {
+	t.Parallel()
+
+	dir := mockProxy(t)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(failing.Close)
+
+	empty := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(empty.Close)
+
+	fallback := moduleFinderFunc(func(context.Context, module.Source) (map[module.Path]module.Version, error) {
+		return map[module.Path]module.Version{".": module.NewVersion(9, 9, 9)}, nil
+	})
+
+	testCases := []struct {
+		scenario       string
+		env            map[string]string
+		fallback       module.Finder
+		expectedResult map[module.Path]module.Version
+		expectedError  string
+	}{
+		{
+			scenario: "fall through on not found",
+			env:      map[string]string{"GOPROXY": empty.URL + ",file://" + dir},
+			expectedResult: map[module.Path]module.Version{
+				".": module.NewVersion(1, 2, 0),
+			},
+		},
+		{
+			scenario:      "stop on error",
+			env:           map[string]string{"GOPROXY": failing.URL + ",file://" + dir},
+			expectedError: "unexpected status code: 500 Internal Server Error",
+		},
+		{
+			scenario: "fall through on error",
+			env:      map[string]string{"GOPROXY": failing.URL + "|file://" + dir},
+			expectedResult: map[module.Path]module.Version{
+				".": module.NewVersion(1, 2, 0),
+			},
+		},
+		{
+			scenario:      "malformed proxy",
+			env:           map[string]string{"GOPROXY": "http://%zz"},
+			expectedError: "could not create request",
+		},
+		{
+			scenario:      "off",
+			env:           map[string]string{"GOPROXY": "off"},
+			expectedError: "module lookup disabled by GOPROXY=off",
+		},
+		{
+			scenario:      "direct without fallback",
+			env:           map[string]string{"GOPROXY": empty.URL + ",direct"},
+			expectedError: "direct module lookup is not supported: example.com/v1only",
+		},
+		{
+			scenario: "direct",
+			env:      map[string]string{"GOPROXY": empty.URL + ",direct"},
+			fallback: fallback,
+			expectedResult: map[module.Path]module.Version{
+				".": module.NewVersion(9, 9, 9),
+			},
+		},
+		{
+			scenario: "no proxy",
+			env:      map[string]string{"GOPROXY": "file://" + dir, "GONOPROXY": "example.com/v1*"},
+			fallback: fallback,
+			expectedResult: map[module.Path]module.Version{
+				".": module.NewVersion(9, 9, 9),
+			},
+		},
+		{
+			scenario: "private",
+			env:      map[string]string{"GOPROXY": "file://" + dir, "GOPRIVATE": "example.com"},
+			fallback: fallback,
+			expectedResult: map[module.Path]module.Version{
+				".": module.NewVersion(9, 9, 9),
+			},
+		},
+		{
+			scenario: "no proxy overrides private",
+			env:      map[string]string{"GOPROXY": "file://" + dir, "GOPRIVATE": "example.com", "GONOPROXY": "none"},
+			fallback: fallback,
+			expectedResult: map[module.Path]module.Version{
+				".": module.NewVersion(1, 2, 0),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			getenv := func(key string) string {
+				return tc.env[key]
+			}
+
+			f := goproxy.NewFinder(goproxy.WithEnv(getenv), goproxy.WithFallback(tc.fallback))
+
+			actual, err := f.Find(t.Context(), module.Source{ImportPath: "example.com/v1only"})
+
+			assert.Equal(t, tc.expectedResult, actual)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+type moduleFinderFunc func(ctx context.Context, src module.Source) (map[module.Path]module.Version, error)
+
+func (f moduleFinderFunc) Find(ctx context.Context, src module.Source) (map[module.Path]module.Version, error) {
+	return f(ctx, src)
+}
+
+// mockProxy creates a file-based proxy, in the same layout as $GOPATH/pkg/mod/cache/download.
+func mockProxy(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	mockProxyModule(t, dir, "example.com/v1only", "example.com/v1only", "v0.1.0", "v1.0.0", "v1.2.0", "v1.3.0-rc.1")
+	mockProxyModule(t, dir, "example.com/lib", "example.com/lib", "v1.0.0", "v1.1.0")
+	mockProxyModule(t, dir, "example.com/lib/v2", "example.com/lib/v2", "v2.0.0", "v2.3.0")
+	mockProxyModule(t, dir, "example.com/lib/contrib", "example.com/lib/contrib", "v0.4.0")
+	mockProxyModule(t, dir, "example.com/lib/contrib/v2", "example.com/lib/contrib/v2", "v2.0.1")
+	mockProxyModule(t, dir, "example.com/v2only/v2", "example.com/v2only/v2", "v2.0.0")
+	mockProxyModule(t, dir, "example.com/gap", "example.com/gap", "v1.0.0")
+	mockProxyModule(t, dir, "example.com/gap/v2", "example.com/gap/v2", "v2.1.0")
+	mockProxyModule(t, dir, "example.com/gap/v4", "example.com/gap/v4", "v4.0.0")
+	mockProxyModule(t, dir, "example.com/noroot/sub", "example.com/noroot/sub", "v0.2.0")
+	mockProxyModule(t, dir, "example.com/!upper", "example.com/Upper", "v0.1.0")
+	mockProxyModule(t, dir, "example.com/mismatch", "example.com/other", "v1.0.0")
+
+	pseudo := "v0.0.0-20240101000000-abcdefabcdef"
+
+	mockProxyModule(t, dir, "example.com/pseudo", "example.com/pseudo", pseudo)
+	writeFile(t, filepath.Join(dir, "example.com/pseudo/@v/list"), "")
+	writeFile(t, filepath.Join(dir, "example.com/pseudo/@latest"), `{"Version":"`+pseudo+`"}`)
+
+	return dir
+}
+
+func mockProxyModule(t *testing.T, dir, escapedPath, modPath string, versions ...string) {
+	t.Helper()
+
+	list := ""
+
+	for _, v := range versions {
+		list += v + "\n"
+
+		writeFile(t, filepath.Join(dir, escapedPath, "@v", v+".mod"), "module "+modPath+"\n")
+	}
+
+	writeFile(t, filepath.Join(dir, escapedPath, "@v", "list"), list)
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+}
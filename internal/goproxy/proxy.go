@@ -0,0 +1,130 @@
+package goproxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	xerrors "go.nhat.io/vanityrender/internal/errors"
+)
+
+const (
+	// DefaultProxy is the default value of GOPROXY.
+	DefaultProxy = "https://proxy.golang.org,direct"
+
+	proxyDirect = "direct"
+	proxyOff    = "off"
+)
+
+const (
+	// ErrNotFound indicates that the proxies do not have the requested content.
+	ErrNotFound = xerrors.Error("not found")
+	// ErrProxyOff indicates that the module lookup is disabled by GOPROXY=off.
+	ErrProxyOff = xerrors.Error("module lookup disabled by GOPROXY=off")
+
+	// errDirect indicates that the module must be fetched directly from its repository.
+	errDirect = xerrors.Error("direct")
+)
+
+// proxy is an entry of GOPROXY.
+type proxy struct {
+	url string
+	// fallbackOnError is true when the next proxy is used on any error, and not only on not found.
+	fallbackOnError bool
+}
+
+// parseProxyList parses GOPROXY. The entries are separated by commas or pipes. After a comma, the next entry is used only
+// if the current one responds with 404 or 410. After a pipe, the next entry is used on any error.
+func parseProxyList(list string) []proxy {
+	var result []proxy
+
+	for len(list) > 0 {
+		var (
+			entry           string
+			fallbackOnError bool
+		)
+
+		if i := strings.IndexAny(list, ",|"); i >= 0 {
+			entry, fallbackOnError, list = list[:i], list[i] == '|', list[i+1:]
+		} else {
+			entry, list = list, ""
+		}
+
+		entry = strings.TrimSpace(entry)
+		if len(entry) == 0 {
+			continue
+		}
+
+		result = append(result, proxy{
+			url:             strings.TrimRight(entry, "/"),
+			fallbackOnError: fallbackOnError,
+		})
+	}
+
+	return result
+}
+
+// get gets the content at the path from the first proxy that has it.
+func get(ctx context.Context, client *http.Client, proxies []proxy, path string) ([]byte, error) {
+	for _, p := range proxies {
+		switch p.url {
+		case proxyOff:
+			return nil, ErrProxyOff
+
+		case proxyDirect:
+			return nil, errDirect
+		}
+
+		data, err := getFromProxy(ctx, client, p.url+"/"+path)
+		if err == nil {
+			return data, nil
+		}
+
+		if !p.fallbackOnError && !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+}
+
+func getFromProxy(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close() // nolint: errcheck
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+
+	case http.StatusNotFound, http.StatusGone:
+		return nil, ErrNotFound
+
+	default:
+		return nil, fmt.Errorf("unexpected status code: %s", resp.Status) // nolint: err113
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read response: %w", err)
+	}
+
+	return data, nil
+}
+
+// newHTTPClient returns a http client that also supports file:// proxies.
+func newHTTPClient() *http.Client {
+	t := http.DefaultTransport.(*http.Transport).Clone() // nolint: forcetypeassert
+	t.RegisterProtocol("file", http.NewFileTransport(http.Dir("/")))
+
+	return &http.Client{Transport: t}
+}
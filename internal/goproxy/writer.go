@@ -0,0 +1,153 @@
+package goproxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	xmodule "golang.org/x/mod/module"
+	"golang.org/x/mod/zip"
+)
+
+// Info is the content of the .info file of a module version.
+type Info struct {
+	Version string
+	Time    time.Time
+}
+
+// Writer writes modules in the layout of a Go module proxy, so the directory can be served by a static file server and
+// used in GOPROXY.
+type Writer struct {
+	dir string
+}
+
+// WriteVersion writes the .info, .mod and .zip files of a module version. A go.mod file is synthesized if goMod is
+// empty, like the go command does for the modules without go.mod file. The module versions are immutable, so the files
+// of the existing versions are kept as is.
+func (w *Writer) WriteVersion(modPath string, info Info, goMod []byte, files []zip.File) error {
+	dir, err := w.versionDir(modPath)
+	if err != nil {
+		return err
+	}
+
+	version, err := xmodule.EscapeVersion(info.Version)
+	if err != nil {
+		return fmt.Errorf("could not escape version: %w", err)
+	}
+
+	base := filepath.Join(dir, version)
+
+	if _, err := os.Stat(base + ".zip"); err == nil {
+		return nil
+	}
+
+	if len(goMod) == 0 {
+		goMod = []byte(fmt.Sprintf("module %s\n", modPath))
+	}
+
+	infoData, err := json.Marshal(info)
+	if err != nil {
+		return fmt.Errorf("could not marshal info: %w", err)
+	}
+
+	if err := writeFile(base+".info", infoData); err != nil {
+		return err
+	}
+
+	if err := writeFile(base+".mod", goMod); err != nil {
+		return err
+	}
+
+	return writeZip(base+".zip", xmodule.Version{Path: modPath, Version: info.Version}, files)
+}
+
+// WriteList writes the list of versions of a module.
+func (w *Writer) WriteList(modPath string, versions []string) error {
+	dir, err := w.versionDir(modPath)
+	if err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+
+	for _, v := range versions {
+		sb.WriteString(v)
+		sb.WriteString("\n")
+	}
+
+	return writeFile(filepath.Join(dir, "list"), []byte(sb.String()))
+}
+
+// HasList returns true if the list of versions of the module is already written.
+func (w *Writer) HasList(modPath string) bool {
+	escaped, err := xmodule.EscapePath(modPath)
+	if err != nil {
+		return false
+	}
+
+	_, err = os.Stat(filepath.Join(w.dir, filepath.FromSlash(escaped), "@v", "list"))
+
+	return err == nil
+}
+
+func (w *Writer) versionDir(modPath string) (string, error) {
+	escaped, err := xmodule.EscapePath(modPath)
+	if err != nil {
+		return "", fmt.Errorf("could not escape module path: %w", err)
+	}
+
+	dir := filepath.Join(w.dir, filepath.FromSlash(escaped), "@v")
+
+	if err := os.MkdirAll(dir, 0o755); err != nil { // nolint: gosec
+		return "", fmt.Errorf("could not create directory: %w", err)
+	}
+
+	return dir, nil
+}
+
+// NewWriter initiates a new Writer that writes into the directory.
+func NewWriter(dir string) *Writer {
+	return &Writer{dir: dir}
+}
+
+func writeFile(name string, data []byte) error {
+	if err := os.WriteFile(name, data, 0o644); err != nil { // nolint: gosec
+		return fmt.Errorf("could not write %s: %w", filepath.Base(name), err)
+	}
+
+	return nil
+}
+
+// writeZip writes the module zip file. The file is written to a temporary file first, so an interrupted run does not
+// leave a broken zip file that would be kept by the next runs.
+func writeZip(name string, m xmodule.Version, files []zip.File) (err error) {
+	f, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("could not create zip file: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = os.Remove(f.Name()) // nolint: errcheck
+		}
+	}()
+
+	if err := zip.Create(f, m, files); err != nil {
+		_ = f.Close() // nolint: errcheck
+
+		return fmt.Errorf("could not create zip file: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("could not write zip file: %w", err)
+	}
+
+	if err := os.Rename(f.Name(), name); err != nil {
+		return fmt.Errorf("could not write zip file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package goproxy_test
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	xmodule "golang.org/x/mod/module"
+	"golang.org/x/mod/zip"
+
+	"go.nhat.io/vanityrender/internal/goproxy"
+	"go.nhat.io/vanityrender/internal/module"
+)
+
+func TestWriter_WriteVersion(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+
+	writeFile(t, filepath.Join(src, "go.mod"), "module example.com/Upper/v2\n")
+	writeFile(t, filepath.Join(src, "upper.go"), "package upper\n")
+	writeFile(t, filepath.Join(src, "vendor", "example.com", "dep", "dep.go"), "package dep\n")
+
+	dir := t.TempDir()
+	w := goproxy.NewWriter(dir)
+	info := goproxy.Info{Version: "v2.1.0", Time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+
+	err := w.WriteVersion("example.com/Upper/v2", info, []byte("module example.com/Upper/v2\n"), localFiles(t, src))
+	require.NoError(t, err)
+
+	err = w.WriteList("example.com/Upper/v2", []string{"v2.1.0"})
+	require.NoError(t, err)
+
+	base := filepath.Join(dir, "example.com", "!upper", "v2", "@v")
+
+	assert.FileExists(t, filepath.Join(base, "v2.1.0.mod"))
+	assert.JSONEq(t, `{"Version":"v2.1.0","Time":"2024-01-02T03:04:05Z"}`, readFile(t, filepath.Join(base, "v2.1.0.info")))
+	assert.Equal(t, "v2.1.0\n", readFile(t, filepath.Join(base, "list")))
+
+	// The zip file is readable by the go command.
+	unzipped := t.TempDir()
+	m := xmodule.Version{Path: "example.com/Upper/v2", Version: "v2.1.0"}
+
+	require.NoError(t, zip.Unzip(unzipped, m, filepath.Join(base, "v2.1.0.zip")))
+
+	assert.FileExists(t, filepath.Join(unzipped, "upper.go"))
+	assert.NoDirExists(t, filepath.Join(unzipped, "vendor"))
+
+	// The module is found through the proxy.
+	f := goproxy.NewFinder(goproxy.WithProxy("file://" + dir))
+
+	actual, err := f.Find(t.Context(), module.Source{ImportPath: "example.com/Upper"})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[module.Path]module.Version{"v2": module.NewVersion(2, 1, 0)}, actual)
+}
+
+func TestWriter_WriteVersion_KeepExisting(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+
+	writeFile(t, filepath.Join(src, "lib.go"), "package lib\n")
+
+	dir := t.TempDir()
+	w := goproxy.NewWriter(dir)
+	info := goproxy.Info{Version: "v1.0.0", Time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+
+	err := w.WriteVersion("example.com/lib", info, nil, localFiles(t, src))
+	require.NoError(t, err)
+
+	base := filepath.Join(dir, "example.com", "lib", "@v")
+
+	// The go.mod file is synthesized.
+	assert.Equal(t, "module example.com/lib\n", readFile(t, filepath.Join(base, "v1.0.0.mod")))
+
+	info.Time = info.Time.Add(time.Hour)
+
+	err = w.WriteVersion("example.com/lib", info, []byte("module example.com/lib\n\ngo 1.24\n"), localFiles(t, src))
+	require.NoError(t, err)
+
+	assert.Equal(t, "module example.com/lib\n", readFile(t, filepath.Join(base, "v1.0.0.mod")))
+	assert.JSONEq(t, `{"Version":"v1.0.0","Time":"2024-01-02T03:04:05Z"}`, readFile(t, filepath.Join(base, "v1.0.0.info")))
+}
+
+func TestWriter_WriteVersion_InvalidVersion(t *testing.T) {
+	t.Parallel()
+
+	w := goproxy.NewWriter(t.TempDir())
+
+	err := w.WriteVersion("example.com/lib/v2", goproxy.Info{Version: "v1.0.0"}, nil, nil)
+
+	assert.ErrorContains(t, err, "could not create zip file")
+}
+
+type localFile struct {
+	root, path string
+}
+
+func (f localFile) Path() string {
+	return f.path
+}
+
+func (f localFile) Lstat() (fs.FileInfo, error) {
+	return os.Lstat(filepath.Join(f.root, f.path))
+}
+
+func (f localFile) Open() (io.ReadCloser, error) {
+	return os.Open(filepath.Join(f.root, f.path))
+}
+
+func localFiles(t *testing.T, root string) []zip.File {
+	t.Helper()
+
+	var files []zip.File
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, localFile{root: root, path: filepath.ToSlash(rel)})
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	return files
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path) //nolint: gosec
+	require.NoError(t, err)
+
+	return string(data)
+}
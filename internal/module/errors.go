@@ -0,0 +1,28 @@
+package module
+
+import "fmt"
+
+var _ error = (*GoModError)(nil)
+
+// GoModError is an error that occurs while reading a go.mod file.
+type GoModError struct {
+	// Repository is the location of the repository, it is empty if unknown.
+	Repository string
+	// File is the path of the go.mod file, relative to the repository root.
+	File string
+	Err  error
+}
+
+// Error returns the error message.
+func (e *GoModError) Error() string {
+	if len(e.Repository) == 0 {
+		return fmt.Sprintf("invalid go.mod file: %s", e.Err)
+	}
+
+	return fmt.Sprintf("%s: invalid go.mod file: %s", e.Repository, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *GoModError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,57 @@
+package module
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// PathFilter selects the modules by their directories relative to the repository root, using glob patterns in the
+// path.Match syntax. A pattern that matches a directory also matches its subdirectories. The root module is always
+// selected.
+type PathFilter struct {
+	// Include selects only the modules that match one of the patterns. Empty means all the modules.
+	Include []string
+	// Exclude drops the modules that match one of the patterns, it has precedence over Include.
+	Exclude []string
+}
+
+// Validate validates the patterns.
+func (f PathFilter) Validate() error {
+	for _, patterns := range [][]string{f.Include, f.Exclude} {
+		for _, p := range patterns {
+			if _, err := path.Match(p, ""); err != nil {
+				return fmt.Errorf("invalid pattern %q: %w", p, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Match checks whether the module in the directory is selected.
+func (f PathFilter) Match(dir string) bool {
+	dir = path.Clean(strings.TrimLeft(dir, "/"))
+
+	if dir == "." {
+		return true
+	}
+
+	if matchAny(f.Exclude, dir) {
+		return false
+	}
+
+	return len(f.Include) == 0 || matchAny(f.Include, dir)
+}
+
+func matchAny(patterns []string, dir string) bool {
+	for _, p := range patterns {
+		for d := dir; d != "."; d = path.Dir(d) {
+			if ok, _ := path.Match(strings.Trim(p, "/"), d); ok { // nolint: errcheck
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,86 @@
+package module_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/vanityrender/internal/module"
+)
+
+func TestPathFilter_Validate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, module.PathFilter{Include: []string{"contrib/*"}, Exclude: []string{"examples"}}.Validate())
+
+	err := module.PathFilter{Exclude: []string{"examples/["}}.Validate()
+
+	assert.EqualError(t, err, `invalid pattern "examples/[": syntax error in pattern`)
+}
+
+func TestPathFilter_Match(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		filter   module.PathFilter
+		dir      string
+		expected bool
+	}{
+		{
+			scenario: "no patterns",
+			dir:      "contrib",
+			expected: true,
+		},
+		{
+			scenario: "root is always selected",
+			filter:   module.PathFilter{Include: []string{"contrib"}, Exclude: []string{"*"}},
+			dir:      ".",
+			expected: true,
+		},
+		{
+			scenario: "included",
+			filter:   module.PathFilter{Include: []string{"contrib/*"}},
+			dir:      "contrib/otel",
+			expected: true,
+		},
+		{
+			scenario: "not included",
+			filter:   module.PathFilter{Include: []string{"contrib/*"}},
+			dir:      "test",
+			expected: false,
+		},
+		{
+			scenario: "excluded",
+			filter:   module.PathFilter{Exclude: []string{"examples"}},
+			dir:      "examples",
+			expected: false,
+		},
+		{
+			scenario: "parent is excluded",
+			filter:   module.PathFilter{Exclude: []string{"examples"}},
+			dir:      "examples/basic",
+			expected: false,
+		},
+		{
+			scenario: "exclude has precedence",
+			filter:   module.PathFilter{Include: []string{"contrib"}, Exclude: []string{"contrib/legacy"}},
+			dir:      "contrib/legacy",
+			expected: false,
+		},
+		{
+			scenario: "leading slash",
+			filter:   module.PathFilter{Exclude: []string{"/examples/"}},
+			dir:      "/examples",
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, tc.filter.Match(tc.dir))
+		})
+	}
+}
@@ -1,12 +1,15 @@
 package module
 
 import (
+	"context"
 	"fmt"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"sort"
+	"strings"
 
 	"golang.org/x/mod/modfile"
 )
@@ -15,48 +18,126 @@ var fileGoModVersionRegExp = regexp.MustCompile(`/(v\d+)$`)
 
 // Finder finds modules.
 type Finder interface {
-	Find(loc, ref string) (map[Path]Version, error)
+	Find(ctx context.Context, src Source) (map[Path]Version, error)
 }
 
-// FindVersions returns the module versions in the given path.
-func FindVersions(dir string) ([]string, error) {
+// ReadmeFinder finds the README files of the modules.
+type ReadmeFinder interface {
+	// FindReadmes returns the README file of each module path, as returned by Finder.Find. The modules without README
+	// files are not in the result.
+	FindReadmes(ctx context.Context, src Source, paths []Path) (map[Path]Readme, error)
+}
+
+// Readme is the README file of a module.
+type Readme struct {
+	// Dir is the directory of the file in the repository, e.g. "sub" for sub/README.md.
+	Dir string
+	// Name is the file name, e.g. README.md.
+	Name    string
+	Content []byte
+}
+
+// Source is where the modules are found.
+type Source struct {
+	// Repository is the location of the repository.
+	Repository string
+	// Ref is the ref of the repository to find the modules at, empty means the default branch.
+	Ref string
+	// ImportPath is the import path of the root module without the major version suffix, e.g. go.nhat.io/vanityrender.
+	ImportPath string
+	// Submodules are the known module paths relative to ImportPath, without the major version suffix.
+	Submodules []string
+	// Filter selects the modules by their directories.
+	Filter PathFilter
+	// AllTags includes the tags that are not reachable from Ref.
+	AllTags bool
+}
+
+// FindVersions returns the module versions in the given path, both the major branch layout (go.mod declares the major
+// version suffix) and the major subdirectory layout (vN/go.mod) are supported. Like the go command, it ignores the vendor and testdata
+// directories, and the directories whose names begin with "." or "_". The modules that are not selected by the filter
+// are ignored too.
+//
+// By default, it stops at the first go.mod file that could not be read, with a *GoModError. Use
+// WithInvalidGoModHandler to skip such modules instead.
+func FindVersions(dir string, filter PathFilter, opts ...FindVersionsOption) ([]string, error) {
+	cfg := findVersionsConfig{}
+
+	for _, o := range opts {
+		o.applyFindVersionsOption(&cfg)
+	}
+
 	var result []string
 
-	err := filepath.Walk(dir, func(path string, info fs.FileInfo, err error) error {
+	err := filepath.WalkDir(dir, func(file string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
-		if !info.IsDir() && info.Name() == goMod {
-			f, err := parseGoMod(path)
-			if err != nil {
-				return err
+		if d.IsDir() {
+			if file != dir && isIgnoredDir(d.Name()) {
+				return filepath.SkipDir
 			}
 
-			rel, err := filepath.Rel(dir, path)
-			if err != nil {
-				return fmt.Errorf("could not get relative path: %w", err)
-			}
+			return nil
+		}
 
-			modulePath := filepath.Dir(rel)
-			version := "v0.0.0"
+		if d.Name() != goMod {
+			return nil
+		}
 
-			if f.Module != nil && fileGoModVersionRegExp.MatchString(f.Module.Mod.Path) {
-				m := fileGoModVersionRegExp.FindStringSubmatch(f.Module.Mod.Path)
-				version = fmt.Sprintf("%s.0.0", m[1])
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return fmt.Errorf("could not get relative path: %w", err)
+		}
+
+		// The modules of the major subdirectory layout, e.g. v2/go.mod, are selected like the module of their parent
+		// directory.
+		modulePath := filepath.ToSlash(filepath.Dir(rel))
+		if !filter.Match(PathWithoutVersion(modulePath)) {
+			return nil
+		}
+
+		f, modErr := parseGoMod(file, filepath.ToSlash(rel))
+		if modErr != nil {
+			if cfg.onInvalidGoMod == nil {
+				return modErr
 			}
 
-			pathVersion := version
-			if modulePath != "." {
-				pathVersion = fmt.Sprintf("%s/%s", modulePath, version)
+			cfg.onInvalidGoMod(modErr)
+
+			return nil
+		}
+
+		// The module is at the zero version of its major until a tag gives it one, the untagged modules are rendered
+		// without version.
+		version := "v0.0.0"
+
+		if f.Module != nil && fileGoModVersionRegExp.MatchString(f.Module.Mod.Path) {
+			m := fileGoModVersionRegExp.FindStringSubmatch(f.Module.Mod.Path)
+			version = fmt.Sprintf("%s.0.0", m[1])
+
+			// Major subdirectory layout, e.g. v2/go.mod declares example.com/module/v2, the directory is already the
+			// major version suffix.
+			if path.Base(modulePath) == m[1] {
+				modulePath = path.Dir(modulePath)
 			}
+		}
 
-			result = append(result, pathVersion)
+		pathVersion := version
+		if modulePath != "." {
+			pathVersion = fmt.Sprintf("%s/%s", modulePath, version)
 		}
 
+		result = append(result, pathVersion)
+
 		return nil
 	})
 	if err != nil {
+		if _, ok := err.(*GoModError); ok { // nolint: errorlint
+			return nil, err
+		}
+
 		return nil, fmt.Errorf("could not walk directory: %w", err)
 	}
 
@@ -65,16 +146,44 @@ func FindVersions(dir string) ([]string, error) {
 	return result, nil
 }
 
-func parseGoMod(file string) (*modfile.File, error) {
+// isIgnoredDir checks whether the directory cannot contain modules, according to the go command.
+func isIgnoredDir(name string) bool {
+	return name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
+
+// parseGoMod parses the go.mod file, the name is the path of the file relative to the repository root.
+func parseGoMod(file, name string) (*modfile.File, *GoModError) {
 	data, err := os.ReadFile(filepath.Clean(file))
 	if err != nil {
-		return nil, fmt.Errorf("could not read file %q: %w", file, err)
+		return nil, &GoModError{File: name, Err: fmt.Errorf("could not read %s: %w", name, err)}
 	}
 
-	f, err := modfile.Parse(file, data, nil)
+	f, err := modfile.Parse(name, data, nil)
 	if err != nil {
-		panic(fmt.Errorf("could not parse mod file: %w", err))
+		return nil, &GoModError{File: name, Err: err}
 	}
 
 	return f, nil
 }
+
+type findVersionsConfig struct {
+	onInvalidGoMod func(err *GoModError)
+}
+
+// FindVersionsOption is an option to configure FindVersions.
+type FindVersionsOption interface {
+	applyFindVersionsOption(c *findVersionsConfig)
+}
+
+type findVersionsOptionFunc func(c *findVersionsConfig)
+
+func (f findVersionsOptionFunc) applyFindVersionsOption(c *findVersionsConfig) {
+	f(c)
+}
+
+// WithInvalidGoModHandler skips the modules whose go.mod files could not be read, and reports them to the handler.
+func WithInvalidGoModHandler(fn func(err *GoModError)) FindVersionsOption {
+	return findVersionsOptionFunc(func(c *findVersionsConfig) {
+		c.onInvalidGoMod = fn
+	})
+}
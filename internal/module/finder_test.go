@@ -18,6 +18,7 @@ func TestFindVersions_Success(t *testing.T) {
 	testCases := []struct {
 		scenario   string
 		mockModule func(t *testing.T) string
+		filter     module.PathFilter
 		expected   []string
 	}{
 		{
@@ -40,13 +41,41 @@ func TestFindVersions_Success(t *testing.T) {
 			mockModule: mockModuleV2WithSubmodules,
 			expected:   []string{"contrib/v0.0.0", "test/v3.0.0", "v2.0.0"},
 		},
+		{
+			scenario:   "major subdirectories",
+			mockModule: mockModuleMajorSubdirectories,
+			expected:   []string{"contrib/v0.0.0", "contrib/v3.0.0", "v0.0.0", "v2.0.0"},
+		},
+		{
+			scenario:   "ignored directories",
+			mockModule: mockModuleWithIgnoredDirs,
+			expected:   []string{"contrib/v0.0.0", "v0.0.0"},
+		},
+		{
+			scenario:   "include",
+			mockModule: mockModuleV0WithSubmodules,
+			filter:     module.PathFilter{Include: []string{"contrib"}},
+			expected:   []string{"contrib/v0.0.0", "v0.0.0"},
+		},
+		{
+			scenario:   "include with major subdirectories",
+			mockModule: mockModuleMajorSubdirectories,
+			filter:     module.PathFilter{Include: []string{"sub/*"}},
+			expected:   []string{"v0.0.0", "v2.0.0"},
+		},
+		{
+			scenario:   "exclude",
+			mockModule: mockModuleV0WithSubmodules,
+			filter:     module.PathFilter{Exclude: []string{"c*"}},
+			expected:   []string{"test/v3.0.0", "v0.0.0"},
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.scenario, func(t *testing.T) {
 			t.Parallel()
 
-			actual, err := module.FindVersions(tc.mockModule(t))
+			actual, err := module.FindVersions(tc.mockModule(t), tc.filter)
 			require.NoError(t, err)
 
 			assert.Equal(t, tc.expected, actual)
@@ -54,6 +83,42 @@ func TestFindVersions_Success(t *testing.T) {
 	}
 }
 
+func TestFindVersions_InvalidGoMod(t *testing.T) {
+	t.Parallel()
+
+	dir := mockModuleV0WithSubmodules(t)
+
+	writeFile(t, filepath.Join(dir, "examples", "go.mod"), "module example.com/module/examples\nunknown directive\n")
+
+	t.Run("abort", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := module.FindVersions(dir, module.PathFilter{})
+
+		assert.Nil(t, actual)
+		assert.EqualError(t, err, "invalid go.mod file: examples/go.mod:2: unknown directive: unknown")
+
+		var modErr *module.GoModError
+
+		require.ErrorAs(t, err, &modErr)
+		assert.Equal(t, "examples/go.mod", modErr.File)
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		t.Parallel()
+
+		var skipped []string
+
+		actual, err := module.FindVersions(dir, module.PathFilter{}, module.WithInvalidGoModHandler(func(err *module.GoModError) {
+			skipped = append(skipped, err.File)
+		}))
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"contrib/v0.0.0", "test/v3.0.0", "v0.0.0"}, actual)
+		assert.Equal(t, []string{"examples/go.mod"}, skipped)
+	})
+}
+
 func mockModuleV0(t *testing.T) string {
 	t.Helper()
 
@@ -98,6 +163,35 @@ func mockModuleV2WithSubmodules(t *testing.T) string {
 	return dir
 }
 
+func mockModuleMajorSubdirectories(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	writeGoMod(t, dir, "example.com/module")
+	writeGoMod(t, filepath.Join(dir, "v2"), "example.com/module/v2")
+	writeGoMod(t, filepath.Join(dir, "contrib"), "example.com/module/contrib")
+	writeGoMod(t, filepath.Join(dir, "contrib", "v3"), "example.com/module/contrib/v3")
+
+	return dir
+}
+
+func mockModuleWithIgnoredDirs(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	writeGoMod(t, dir, "example.com/module")
+	writeGoMod(t, filepath.Join(dir, "contrib"), "example.com/module/contrib")
+	writeGoMod(t, filepath.Join(dir, "contrib", "testdata", "fixture"), "example.com/fixture")
+	writeGoMod(t, filepath.Join(dir, "vendor", "example.com", "dep"), "example.com/dep")
+	writeGoMod(t, filepath.Join(dir, ".github", "tools"), "example.com/module/.github/tools")
+	writeGoMod(t, filepath.Join(dir, "_examples"), "example.com/module/_examples")
+	writeFile(t, filepath.Join(dir, ".git", "go.mod"), "broken")
+
+	return dir
+}
+
 func writeFile(t *testing.T, path, data string) {
 	t.Helper()
 
@@ -7,7 +7,6 @@ import (
 	"strings"
 
 	xerrors "go.nhat.io/vanityrender/internal/errors"
-	"go.nhat.io/vanityrender/internal/must"
 )
 
 const (
@@ -67,24 +66,24 @@ func (v Version) String() string {
 	return fmt.Sprintf("v%d.%d.%d", v.Major, v.Minor, v.Patch)
 }
 
-// PathVersion returns the path and version from a module version string.
+// PathVersion returns the path and version from a module version string, the path has the major version suffix from v2,
+// e.g. contrib/v2.1.0 and contrib/v2/v2.1.0 (major subdirectory layout) are both contrib/v2. The path is empty if the
+// string is invalid.
 func PathVersion(s string) (Path, Version) {
 	if PathVersionRegExp.MatchString(s) {
-		i := strings.LastIndex(s, "/")
-
-		var (
-			path    Path
-			version Version
-		)
-
-		if i == -1 {
-			path, version = ".", NewVersionFromString(s)
-		} else {
-			path = Path(s[:i])
-			version = NewVersionFromString(s[i+1:])
+		path, v := Path("."), s
+
+		if i := strings.LastIndex(s, "/"); i >= 0 {
+			path, v = Path(s[:i]), s[i+1:]
+		}
+
+		version, err := ParseVersion(v)
+		if err != nil {
+			return "", NewVersion(0, 0, 0)
 		}
 
-		if version.Major > 1 {
+		// The path already has the major version suffix in the major subdirectory layout, e.g. v2/v2.0.0.
+		if version.Major > 1 && !hasMajorSuffix(path, version.Major) {
 			path = Path(PathWithVersion(path, version))
 		}
 
@@ -94,28 +93,32 @@ func PathVersion(s string) (Path, Version) {
 	return "", NewVersion(0, 0, 0)
 }
 
-// NewVersionFromString returns a new version from a string.
-func NewVersionFromString(s string) Version {
+func hasMajorSuffix(p Path, major int) bool {
+	s := string(p)
+
+	return s == fmt.Sprintf("v%d", major) || strings.HasSuffix(s, fmt.Sprintf("/v%d", major))
+}
+
+// ParseVersion parses a version string, e.g. v1.2.3.
+func ParseVersion(s string) (Version, error) {
 	m := VersionRegExp.FindStringSubmatch(s)
 
 	if len(m) == 0 {
-		panic(fmt.Errorf("invalid version: %w", ErrInvalidVersion))
+		return Version{}, fmt.Errorf("%w: %q", ErrInvalidVersion, s)
 	}
 
-	major, err := strconv.Atoi(m[1])
-	must.NoError(err)
-
-	minor, err := strconv.Atoi(m[2])
-	must.NoError(err)
+	var (
+		parts [3]int
+		err   error
+	)
 
-	patch, err := strconv.Atoi(m[3])
-	must.NoError(err)
-
-	return Version{
-		Major: major,
-		Minor: minor,
-		Patch: patch,
+	for i := range parts {
+		if parts[i], err = strconv.Atoi(m[i+1]); err != nil {
+			return Version{}, fmt.Errorf("%w: %q", ErrInvalidVersion, s)
+		}
 	}
+
+	return NewVersion(parts[0], parts[1], parts[2]), nil
 }
 
 // NewVersion returns a new version.
@@ -117,6 +117,59 @@ func TestVersion_String(t *testing.T) {
 	assert.Equal(t, expected, actual)
 }
 
+func TestParseVersion(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario        string
+		value           string
+		expectedVersion module.Version
+		expectedError   string
+	}{
+		{
+			scenario:      "empty string",
+			expectedError: `invalid version: ""`,
+		},
+		{
+			scenario:      "not a version",
+			value:         "master",
+			expectedError: `invalid version: "master"`,
+		},
+		{
+			scenario:      "overflow",
+			value:         "v99999999999999999999.0.0",
+			expectedError: `invalid version: "v99999999999999999999.0.0"`,
+		},
+		{
+			scenario:        "with prefix",
+			value:           "v1.2.3",
+			expectedVersion: module.NewVersion(1, 2, 3),
+		},
+		{
+			scenario:        "without prefix",
+			value:           "1.2.3",
+			expectedVersion: module.NewVersion(1, 2, 3),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := module.ParseVersion(tc.value)
+
+			assert.Equal(t, tc.expectedVersion, actual)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, module.ErrInvalidVersion)
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
 func TestPathVersion(t *testing.T) {
 	t.Parallel()
 
@@ -170,6 +223,22 @@ func TestPathVersion(t *testing.T) {
 			expectedPath:    "contrib/v2",
 			expectedVersion: module.NewVersion(2, 3, 0),
 		},
+		{
+			scenario:        "major subdirectory - v2",
+			value:           "v2/v2.0.0",
+			expectedPath:    "v2",
+			expectedVersion: module.NewVersion(2, 0, 0),
+		},
+		{
+			scenario:        "major subdirectory - submodule v3",
+			value:           "contrib/v3/v3.1.0",
+			expectedPath:    "contrib/v3",
+			expectedVersion: module.NewVersion(3, 1, 0),
+		},
+		{
+			scenario: "version overflow",
+			value:    "contrib/v99999999999999999999.0.0",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,117 @@
+package repoinfo
+
+import (
+	"bufio"
+	"bytes"
+	"path"
+	"regexp"
+	"strings"
+)
+
+var (
+	readmeFiles = []string{"readme.md", "readme.markdown", "readme", "readme.txt", "readme.rst"}
+
+	markdownImageRegExp     = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)`)
+	markdownLinkRegExp      = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	markdownEmphasisRegExp  = regexp.MustCompile("(\\*\\*|__|`)")
+	markdownUnderlineRegExp = regexp.MustCompile(`^(=+|-+)$`)
+	htmlTagRegExp           = regexp.MustCompile(`<[^>]+>`)
+	spacesRegExp            = regexp.MustCompile(`\s+`)
+)
+
+// ReadmeRank returns the preference of a README file, the lower the better, e.g. README.md is preferred over README, or
+// -1 if the file is not a README file. The name is case-insensitive.
+func ReadmeRank(name string) int {
+	return fileIndex(readmeFiles, name)
+}
+
+// Description returns the first paragraph of a README file as plain text. The headings, the badges, the images and the
+// HTML blocks are skipped, and the links are replaced by their texts.
+func Description(readme []byte) string {
+	var (
+		paragraph []string
+		inCode    bool
+		inComment bool
+	)
+
+	s := bufio.NewScanner(bytes.NewReader(readme))
+
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+
+		switch {
+		case strings.HasPrefix(line, "```"), strings.HasPrefix(line, "~~~"):
+			inCode = !inCode
+
+			continue
+
+		case inCode:
+			continue
+
+		case strings.HasPrefix(line, "<!--"):
+			inComment = !strings.Contains(line, "-->")
+
+			continue
+
+		case inComment:
+			inComment = !strings.Contains(line, "-->")
+
+			continue
+		}
+
+		// The lines above a setext underline are a heading, not a paragraph.
+		if markdownUnderlineRegExp.MatchString(line) {
+			paragraph = nil
+
+			continue
+		}
+
+		if isParagraphBreak(line) {
+			if len(paragraph) > 0 {
+				break
+			}
+
+			continue
+		}
+
+		if text := plainText(line); len(text) > 0 {
+			paragraph = append(paragraph, text)
+		}
+	}
+
+	return strings.Join(paragraph, " ")
+}
+
+// isParagraphBreak returns true if the line ends a paragraph or is not a part of one.
+func isParagraphBreak(line string) bool {
+	return len(line) == 0 ||
+		strings.HasPrefix(line, "#") ||
+		strings.HasPrefix(line, "<") ||
+		strings.HasPrefix(line, ">") ||
+		strings.HasPrefix(line, "|") ||
+		strings.HasPrefix(line, "- ") ||
+		strings.HasPrefix(line, "* ")
+}
+
+// plainText removes the markdown and the HTML from a line.
+func plainText(line string) string {
+	line = markdownImageRegExp.ReplaceAllString(line, "")
+	line = markdownLinkRegExp.ReplaceAllString(line, "$1")
+	line = markdownEmphasisRegExp.ReplaceAllString(line, "")
+	line = htmlTagRegExp.ReplaceAllString(line, "")
+
+	return strings.TrimSpace(spacesRegExp.ReplaceAllString(line, " "))
+}
+
+// fileIndex returns the index of the file name in the list, case-insensitive, -1 if not found.
+func fileIndex(names []string, name string) int {
+	name = strings.ToLower(path.Base(name))
+
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+
+	return -1
+}
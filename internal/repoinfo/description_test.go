@@ -0,0 +1,77 @@
+package repoinfo_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/vanityrender/internal/repoinfo"
+)
+
+func TestReadmeRank(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0, repoinfo.ReadmeRank("README.md"))
+	assert.Equal(t, 0, repoinfo.ReadmeRank("readme.md"))
+	assert.Less(t, repoinfo.ReadmeRank("README.md"), repoinfo.ReadmeRank("README"))
+	assert.Equal(t, -1, repoinfo.ReadmeRank("README.go"))
+	assert.Equal(t, -1, repoinfo.ReadmeRank("LICENSE"))
+}
+
+func TestDescription(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		readme   string
+		expected string
+	}{
+		{
+			scenario: "empty",
+		},
+		{
+			scenario: "only headings",
+			readme:   "# Title\n\n## Usage\n",
+		},
+		{
+			scenario: "first paragraph",
+			readme: `# Vanity Render
+
+[![GitHub Releases](https://img.shields.io/github/v/release/nhatthm/govanityrender)](https://github.com/nhatthm/govanityrender/releases/latest)
+[![Build Status](https://github.com/nhatthm/govanityrender/actions/workflows/test.yaml/badge.svg)](https://github.com/nhatthm/govanityrender/actions/workflows/test.yaml)
+
+<!-- A comment
+that spans two lines -->
+
+A **static** site generator for the [vanity URLs](https://sagikazarmark.hu/blog/vanity-import-paths-in-go/)
+of ` + "`Go`" + ` modules.
+
+It renders the pages.
+`,
+			expected: "A static site generator for the vanity URLs of Go modules.",
+		},
+		{
+			scenario: "setext heading",
+			readme:   "Vanity Render\n=============\n\nRenders the pages.\n",
+			expected: "Renders the pages.",
+		},
+		{
+			scenario: "code block is skipped",
+			readme:   "```shell\n$ go install\n```\n\nInstall it.\n",
+			expected: "Install it.",
+		},
+		{
+			scenario: "html is removed",
+			readme:   "<p align=\"center\">\n<img src=\"logo.png\">\n</p>\n\nThe <b>best</b> renderer.\n",
+			expected: "The best renderer.",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, repoinfo.Description([]byte(tc.readme)))
+		})
+	}
+}
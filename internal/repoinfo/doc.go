@@ -0,0 +1,2 @@
+// Package repoinfo provides functionalities to describe a repository from its README and LICENSE files.
+package repoinfo
@@ -0,0 +1,70 @@
+package repoinfo
+
+import (
+	"regexp"
+	"strings"
+)
+
+var licenseFiles = []string{"license", "license.md", "license.txt", "licence", "licence.md", "licence.txt", "copying", "copying.md", "copying.txt"}
+
+// licenseHeadSize is the size of the head of a normalized license text where the title of a license is looked up. The
+// texts of the licenses mention the other licenses, e.g. the GPL mentions the LGPL and the AGPL, but only in their bodies.
+const licenseHeadSize = 300
+
+// licenses are the SPDX identifiers of the licenses with the phrases of their titles, that are looked up in the head of
+// the text, or the phrases of their bodies, that are looked up in the whole text. The most specific licenses go first.
+var licenses = []struct {
+	id      string
+	title   []string
+	phrases []string
+}{
+	{id: "AGPL-3.0", title: []string{"gnu affero general public license", "version 3"}},
+	{id: "LGPL-3.0", title: []string{"gnu lesser general public license", "version 3"}},
+	{id: "LGPL-2.1", title: []string{"gnu lesser general public license", "version 2.1"}},
+	{id: "GPL-3.0", title: []string{"gnu general public license", "version 3"}},
+	{id: "GPL-2.0", title: []string{"gnu general public license", "version 2"}},
+	{id: "MPL-2.0", title: []string{"mozilla public license", "version 2.0"}},
+	{id: "Apache-2.0", title: []string{"apache license", "version 2.0"}},
+	{id: "BSD-3-Clause", phrases: []string{"redistribution and use in source and binary forms", "neither the name"}},
+	{id: "BSD-2-Clause", phrases: []string{"redistribution and use in source and binary forms"}},
+	{id: "MIT", phrases: []string{"permission is hereby granted, free of charge"}},
+	{id: "ISC", phrases: []string{"permission to use, copy, modify, and/or distribute this software for any purpose"}},
+	{id: "Unlicense", phrases: []string{"this is free and unencumbered software released into the public domain"}},
+}
+
+var licenseSpacesRegExp = regexp.MustCompile(`[\s#*>]+`)
+
+// LicenseRank returns the preference of a license file, the lower the better, e.g. LICENSE is preferred over COPYING, or
+// -1 if the file is not a license file. The name is case-insensitive.
+func LicenseRank(name string) int {
+	return fileIndex(licenseFiles, name)
+}
+
+// License returns the SPDX identifier of the license in the text of a license file, e.g. MIT or Apache-2.0, or an empty
+// string if the license is unknown.
+func License(text []byte) string {
+	normalized := strings.ToLower(strings.TrimSpace(licenseSpacesRegExp.ReplaceAllString(string(text), " ")))
+
+	head := normalized
+	if len(head) > licenseHeadSize {
+		head = head[:licenseHeadSize]
+	}
+
+	for _, l := range licenses {
+		if containsAll(head, l.title) && containsAll(normalized, l.phrases) {
+			return l.id
+		}
+	}
+
+	return ""
+}
+
+func containsAll(s string, phrases []string) bool {
+	for _, p := range phrases {
+		if !strings.Contains(s, p) {
+			return false
+		}
+	}
+
+	return true
+}
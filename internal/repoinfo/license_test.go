@@ -0,0 +1,124 @@
+package repoinfo_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/vanityrender/internal/repoinfo"
+)
+
+func TestLicenseRank(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0, repoinfo.LicenseRank("LICENSE"))
+	assert.Less(t, repoinfo.LicenseRank("license.md"), repoinfo.LicenseRank("COPYING"))
+	assert.Equal(t, -1, repoinfo.LicenseRank("README.md"))
+}
+
+func TestLicense(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		text     string
+		expected string
+	}{
+		{
+			scenario: "empty",
+		},
+		{
+			scenario: "unknown",
+			text:     "All rights reserved.",
+		},
+		{
+			scenario: "MIT",
+			text: `MIT License
+
+Copyright (c) 2022 Nhat
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal`,
+			expected: "MIT",
+		},
+		{
+			scenario: "Apache-2.0",
+			text: `
+                                 Apache License
+                           Version 2.0, January 2004
+                        http://www.apache.org/licenses/`,
+			expected: "Apache-2.0",
+		},
+		{
+			scenario: "BSD-3-Clause",
+			text: `Copyright (c) 2009 The Go Authors. All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Neither the name of Google Inc. nor the names of its
+contributors may be used to endorse or promote products derived from`,
+			expected: "BSD-3-Clause",
+		},
+		{
+			scenario: "BSD-2-Clause",
+			text: `Copyright (c) 2013 The Authors.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:`,
+			expected: "BSD-2-Clause",
+		},
+		{
+			scenario: "GPL-3.0 mentions the other GNU licenses in its body",
+			text: `                    GNU GENERAL PUBLIC LICENSE
+                       Version 3, 29 June 2007
+
+ Copyright (C) 2007 Free Software Foundation, Inc. <https://fsf.org/>
+ Everyone is permitted to copy and distribute verbatim copies
+ of this license document, but changing it is not allowed.
+
+` + padding() + `
+  13. Use with the GNU Affero General Public License.
+
+use the GNU Lesser General Public License instead of this License.`,
+			expected: "GPL-3.0",
+		},
+		{
+			scenario: "LGPL-2.1",
+			text: `                  GNU LESSER GENERAL PUBLIC LICENSE
+                       Version 2.1, February 1999`,
+			expected: "LGPL-2.1",
+		},
+		{
+			scenario: "MPL-2.0 mentions the GNU licenses in its body",
+			text: `Mozilla Public License Version 2.0
+==================================
+
+` + padding() + `
+    means either the GNU General Public License, Version 2.0, the GNU
+    Lesser General Public License, Version 2.1, the GNU Affero General
+    Public License, Version 3.0`,
+			expected: "MPL-2.0",
+		},
+		{
+			scenario: "markdown",
+			text:     "# The MIT License\n\n> Permission is hereby granted,\n> free of charge, to any person",
+			expected: "MIT",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, repoinfo.License([]byte(tc.text)))
+		})
+	}
+}
+
+// padding returns a text that pushes the next lines out of the head of the license.
+func padding() string {
+	return strings.Repeat("Lorem ipsum dolor sit amet, consectetur adipiscing elit.\n", 10)
+}
@@ -0,0 +1,129 @@
+package repoinfo
+
+import (
+	"bytes"
+	"html"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+)
+
+var (
+	markdownFiles = []string{".md", ".markdown"}
+
+	markdown = goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		// The raw HTML, e.g. the centered logos, is kept and sanitized afterward.
+		goldmark.WithRendererOptions(goldmarkhtml.WithUnsafe()),
+	)
+
+	readmePolicy = newReadmePolicy()
+)
+
+// ReadmeHTML renders a README file to sanitized HTML. The markdown files are rendered with the GitHub flavor, the other
+// ones as preformatted text. The relative links and images are resolved against dir, the directory of the file in the
+// repository, and point to blobURL, e.g. https://github.com/nhatthm/govanityrender/blob/HEAD. The links starting with /
+// are relative to the root of the repository.
+func ReadmeHTML(name string, readme []byte, blobURL, dir string) (string, error) {
+	if len(bytes.TrimSpace(readme)) == 0 {
+		return "", nil
+	}
+
+	if !isMarkdown(name) {
+		return "<pre>" + html.EscapeString(string(readme)) + "</pre>", nil
+	}
+
+	doc := markdown.Parser().Parse(text.NewReader(readme))
+
+	err := ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch n := n.(type) {
+		case *ast.Link:
+			n.Destination = []byte(resolveLink(string(n.Destination), blobURL, dir, false))
+
+		case *ast.Image:
+			n.Destination = []byte(resolveLink(string(n.Destination), blobURL, dir, true))
+		}
+
+		return ast.WalkContinue, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+
+	if err := markdown.Renderer().Render(&buf, readme, doc); err != nil {
+		return "", err
+	}
+
+	return readmePolicy.Sanitize(buf.String()), nil
+}
+
+// resolveLink returns the blob URL of a relative link, the absolute links and the anchors are kept. The images link to
+// their raw content.
+func resolveLink(link, blobURL, dir string, image bool) string {
+	u, err := url.Parse(link)
+	if err != nil || u.IsAbs() || len(u.Host) > 0 || len(u.Path) == 0 {
+		return link
+	}
+
+	p := u.Path
+	if !strings.HasPrefix(p, "/") {
+		p = path.Join("/", dir, p)
+	}
+
+	// The links can not go above the root of the repository.
+	result := strings.TrimSuffix(blobURL, "/") + path.Clean(p)
+
+	if image {
+		u.RawQuery = "raw=true"
+	}
+
+	if len(u.RawQuery) > 0 {
+		result += "?" + u.RawQuery
+	}
+
+	if len(u.Fragment) > 0 {
+		result += "#" + u.EscapedFragment()
+	}
+
+	return result
+}
+
+// isMarkdown returns true if the file is a markdown file, by its extension.
+func isMarkdown(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+
+	for _, e := range markdownFiles {
+		if e == ext {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newReadmePolicy returns the sanitization policy of the user generated content, that also keeps the languages of the
+// code blocks and the alignment of the HTML blocks.
+func newReadmePolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	p.AllowAttrs("align").Matching(regexp.MustCompile(`^(?i)(left|center|right)$`)).OnElements("p", "div", "h1", "h2", "h3", "img")
+
+	return p
+}
@@ -0,0 +1,95 @@
+package repoinfo_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/vanityrender/internal/repoinfo"
+)
+
+func TestReadmeHTML(t *testing.T) {
+	t.Parallel()
+
+	const blobURL = "https://github.com/nhatthm/go-mock/blob/HEAD"
+
+	testCases := []struct {
+		scenario string
+		name     string
+		readme   string
+		dir      string
+		expected string
+	}{
+		{
+			scenario: "empty",
+			name:     "README.md",
+			readme:   "\n  \n",
+		},
+		{
+			scenario: "markdown",
+			name:     "README.md",
+			readme:   "# Mock\n\nA **mock** library.\n\n```go\nmock.New()\n```\n",
+			dir:      ".",
+			expected: "<h1 id=\"mock\">Mock</h1>\n<p>A <strong>mock</strong> library.</p>\n<pre><code class=\"language-go\">mock.New()\n</code></pre>\n",
+		},
+		{
+			scenario: "github flavor",
+			name:     "readme.markdown",
+			readme:   "| a | b |\n|---|---|\n| 1 | ~~2~~ |\n",
+			dir:      ".",
+			expected: "<table>\n<thead>\n<tr>\n<th>a</th>\n<th>b</th>\n</tr>\n</thead>\n<tbody>\n<tr>\n<td>1</td>\n<td><del>2</del></td>\n</tr>\n</tbody>\n</table>\n",
+		},
+		{
+			scenario: "relative links",
+			name:     "README.md",
+			readme:   "[docs](docs/usage.md#install) [up](../LICENSE) [root](/go.mod) [above](../../../x.go) [anchor](#usage)",
+			dir:      "sub",
+			expected: `<p><a href="https://github.com/nhatthm/go-mock/blob/HEAD/sub/docs/usage.md#install" rel="nofollow">docs</a> ` +
+				`<a href="https://github.com/nhatthm/go-mock/blob/HEAD/LICENSE" rel="nofollow">up</a> ` +
+				`<a href="https://github.com/nhatthm/go-mock/blob/HEAD/go.mod" rel="nofollow">root</a> ` +
+				`<a href="https://github.com/nhatthm/go-mock/blob/HEAD/x.go" rel="nofollow">above</a> ` +
+				`<a href="#usage" rel="nofollow">anchor</a></p>` + "\n",
+		},
+		{
+			scenario: "relative images",
+			name:     "README.md",
+			readme:   "![logo](.github/logo.png)",
+			dir:      ".",
+			expected: `<p><img src="https://github.com/nhatthm/go-mock/blob/HEAD/.github/logo.png?raw=true" alt="logo"></p>` + "\n",
+		},
+		{
+			scenario: "absolute links",
+			name:     "README.md",
+			readme:   "[site](https://go.nhat.io) [mail](mailto:john.doe@example.com) [cdn](//cdn.example.com/x.js)",
+			dir:      ".",
+			expected: `<p><a href="https://go.nhat.io" rel="nofollow">site</a> ` +
+				`<a href="mailto:john.doe@example.com" rel="nofollow">mail</a> ` +
+				`<a href="//cdn.example.com/x.js" rel="nofollow">cdn</a></p>` + "\n",
+		},
+		{
+			scenario: "sanitized html",
+			name:     "README.md",
+			readme:   "<p align=\"center\" onclick=\"alert(1)\">Logo</p>\n\n<script>alert(1)</script>\n\n[x](javascript:alert(1))\n",
+			dir:      ".",
+			expected: "<p align=\"center\">Logo</p>\n\n<p>x</p>\n",
+		},
+		{
+			scenario: "plain text",
+			name:     "README",
+			readme:   "Mock <library>\n",
+			expected: "<pre>Mock &lt;library&gt;\n</pre>",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := repoinfo.ReadmeHTML(tc.name, []byte(tc.readme), blobURL, tc.dir)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -35,13 +35,14 @@ type metadata struct {
 type Hydrator struct {
 	client *http.Client
 
-	output   io.Writer
-	checksum string
-	timeout  time.Duration
+	output         io.Writer
+	checksum       string
+	ignoreChecksum bool
+	timeout        time.Duration
 }
 
-func (h *Hydrator) metadata(host string) (*metadata, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+func (h *Hydrator) metadata(ctx context.Context, host string) (*metadata, error) {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
 	defer cancel()
 
 	url := fmt.Sprintf("http://%s/%s", host, metadataFile)
@@ -72,17 +73,17 @@ func (h *Hydrator) metadata(host string) (*metadata, error) {
 }
 
 // Hydrate hydrates configuration using the metadata file.
-func (h *Hydrator) Hydrate(s *site.Site) error {
+func (h *Hydrator) Hydrate(ctx context.Context, s *site.Site) error {
 	if len(s.Hostname) == 0 {
 		return nil
 	}
 
-	m, err := h.metadata(s.Hostname)
+	m, err := h.metadata(ctx, s.Hostname)
 	if err != nil {
 		return err
 	}
 
-	if h.checksum != m.Checksum {
+	if !h.ignoreChecksum && h.checksum != m.Checksum {
 		return ErrChecksumMismatched
 	}
 
@@ -117,3 +118,10 @@ type hydratorOptionFunc func(r *Hydrator)
 func (f hydratorOptionFunc) applyHydratorOption(r *Hydrator) {
 	f(r)
 }
+
+// WithoutChecksumValidation uses the metadata file even if it was rendered from a different configuration.
+func WithoutChecksumValidation() HydratorOption {
+	return hydratorOptionFunc(func(r *Hydrator) {
+		r.ignoreChecksum = true
+	})
+}
@@ -18,7 +18,7 @@ func TestMetadataHydrator_Hydrate_Error_CouldNotSendRequest(t *testing.T) {
 
 	h := sitecache.NewMetadataHydrator("123")
 
-	actual := h.Hydrate(&site.Site{Hostname: "https://localhost"})
+	actual := h.Hydrate(t.Context(), &site.Site{Hostname: "https://localhost"})
 	expected := `failed to send request: Get "http://https//localhost/metadata.v1.json":`
 
 	assert.ErrorContains(t, actual, expected)
@@ -33,7 +33,7 @@ func TestMetadataHydrator_Hydrate_MissingHostname(t *testing.T) {
 		PageTitle: "test",
 	}
 
-	err := h.Hydrate(&actual)
+	err := h.Hydrate(t.Context(), &actual)
 	require.NoError(t, err)
 
 	expected := site.Site{
@@ -109,7 +109,7 @@ func TestMetadataHydrator_Hydrate(t *testing.T) {
 				Hostname: tc.mockServer(t),
 			}
 
-			err := h.Hydrate(&actual)
+			err := h.Hydrate(t.Context(), &actual)
 
 			tc.expectedResult.Hostname = actual.Hostname
 
@@ -124,6 +124,24 @@ func TestMetadataHydrator_Hydrate(t *testing.T) {
 	}
 }
 
+func TestMetadataHydrator_Hydrate_WithoutChecksumValidation(t *testing.T) {
+	t.Parallel()
+
+	h := sitecache.NewMetadataHydrator("123", sitecache.WithoutChecksumValidation())
+
+	actual := site.Site{
+		Hostname: mockMetadataServer(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"checksum": "456", "page_title":"Test"}`)) // nolint: errcheck
+		})(t),
+	}
+
+	err := h.Hydrate(t.Context(), &actual)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Test", actual.PageTitle)
+}
+
 func mockMetadataServer(h http.HandlerFunc) func(t *testing.T) string {
 	return mockServer(func(srv *http.ServeMux) {
 		srv.Handle("/metadata.v1.json", h)
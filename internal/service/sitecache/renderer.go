@@ -1,6 +1,7 @@
 package sitecache
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -24,8 +25,8 @@ type Renderder struct {
 }
 
 // Render renders the site.
-func (r *Renderder) Render(s site.Site) error {
-	if err := r.upstream.Render(s); err != nil {
+func (r *Renderder) Render(ctx context.Context, s site.Site) error {
+	if err := r.upstream.Render(ctx, s); err != nil {
 		return err // nolint: errcheck
 	}
 
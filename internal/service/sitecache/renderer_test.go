@@ -2,6 +2,7 @@ package sitecache_test
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"os"
 	"path/filepath"
@@ -20,7 +21,7 @@ func TestRenderder_Render_UpstreamError(t *testing.T) {
 	upstream := mockRenderError(errors.New("upstream error"))
 	h := sitecache.NewRenderder(upstream, "", "")
 
-	err := h.Render(site.Site{})
+	err := h.Render(t.Context(), site.Site{})
 
 	expected := `upstream error`
 
@@ -32,7 +33,7 @@ func TestRenderder_Render_CouldNotWriteMetadata(t *testing.T) {
 
 	h := sitecache.NewRenderder(mockRender(), "unknown", "123")
 
-	err := h.Render(site.Site{})
+	err := h.Render(t.Context(), site.Site{})
 
 	expected := `could not render metadata: open unknown/metadata.v1.json: no such file or directory`
 
@@ -45,7 +46,7 @@ func TestRenderder_Render_Success(t *testing.T) {
 	outputDir := t.TempDir()
 	h := sitecache.NewRenderder(mockRender(), outputDir, "123")
 
-	err := h.Render(site.Site{
+	err := h.Render(t.Context(), site.Site{
 		PageTitle: "test",
 	})
 	require.NoError(t, err)
@@ -76,7 +77,7 @@ func fileContent(t *testing.T, path string) string {
 
 type renderFunc func(s site.Site) error
 
-func (r renderFunc) Render(s site.Site) error {
+func (r renderFunc) Render(_ context.Context, s site.Site) error {
 	return r(s)
 }
 
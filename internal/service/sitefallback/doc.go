@@ -0,0 +1,2 @@
+// Package sitefallback provides functionalities for falling back to the previously published data.
+package sitefallback
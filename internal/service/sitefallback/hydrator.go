@@ -0,0 +1,149 @@
+package sitefallback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/fatih/color"
+
+	"go.nhat.io/vanityrender/internal/module"
+	"go.nhat.io/vanityrender/internal/site"
+)
+
+var _ site.Hydrator = (*Hydrator)(nil)
+
+// Hydrator is a site.Hydrator that replaces the repositories that could not be hydrated with their previously published
+// data.
+type Hydrator struct {
+	cache    site.Hydrator
+	upstream site.Hydrator
+
+	output io.Writer
+}
+
+// Hydrate hydrates the site configuration.
+func (h *Hydrator) Hydrate(ctx context.Context, s *site.Site) error {
+	err := h.upstream.Hydrate(ctx, s)
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+
+	failures := site.RepositoryErrors(err)
+	if len(failures) == 0 {
+		return err
+	}
+
+	cached := site.Site{Hostname: s.Hostname}
+
+	if cErr := h.cache.Hydrate(ctx, &cached); cErr != nil {
+		_, _ = fmt.Fprintln(h.output, color.HiRedString("Cache Error"), ":", cErr.Error()) //nolint: errcheck
+
+		return err
+	}
+
+	cachedRepos := repositoriesMap(cached.Repositories)
+	indexes := repositoriesIndex(s.Repositories)
+	unresolved := make([]error, 0, len(failures))
+
+	for _, f := range failures {
+		path := module.PathWithoutVersion(f.Path)
+
+		c, ok := cachedRepos[path]
+		if !ok {
+			unresolved = append(unresolved, f)
+
+			continue
+		}
+
+		_, _ = fmt.Fprintln(h.output, color.HiYellowString("Fallback"), ":", path) //nolint: errcheck
+
+		i := indexes[path]
+		s.Repositories[i] = restoreRepository(s.Repositories[i], c)
+	}
+
+	if len(unresolved) > 0 {
+		return errors.Join(unresolved...)
+	}
+
+	return fmt.Errorf("%w: %w", site.ErrStaleData, err)
+}
+
+// NewHydrator initiates a new site.Hydrator.
+func NewHydrator(cache site.Hydrator, upstream site.Hydrator, opts ...HydratorOption) *Hydrator {
+	h := &Hydrator{
+		cache:    cache,
+		upstream: upstream,
+		output:   io.Discard,
+	}
+
+	for _, o := range opts {
+		o.applyHydratorOption(h)
+	}
+
+	return h
+}
+
+// restoreRepository returns the configured repository with the hydrated data of its previously published version.
+func restoreRepository(r, cached site.Repository) site.Repository {
+	r.RepositoryURL = cached.RepositoryURL
+	r.RepositoryName = cached.RepositoryName
+	r.LatestVersion = cached.LatestVersion
+	r.LatestPath = cached.LatestPath
+	r.Modules = cached.Modules
+
+	// The metadata published before the latest major path was introduced has it in the path.
+	if len(r.LatestPath) == 0 && cached.Path != r.Path {
+		r.LatestPath = cached.Path
+	}
+
+	// The description and the license are read from the repository when they are not configured.
+	if len(r.Description) == 0 {
+		r.Description = cached.Description
+	}
+
+	if len(r.License) == 0 {
+		r.License = cached.License
+	}
+
+	return r
+}
+
+func repositoriesMap(repos []site.Repository) map[string]site.Repository {
+	m := make(map[string]site.Repository, len(repos))
+
+	for _, r := range repos {
+		m[module.PathWithoutVersion(r.Path)] = r
+	}
+
+	return m
+}
+
+func repositoriesIndex(repos []site.Repository) map[string]int {
+	m := make(map[string]int, len(repos))
+
+	for i, r := range repos {
+		m[module.PathWithoutVersion(r.Path)] = i
+	}
+
+	return m
+}
+
+// HydratorOption is an option to configure Hydrator.
+type HydratorOption interface {
+	applyHydratorOption(r *Hydrator)
+}
+
+type hydratorOptionFunc func(r *Hydrator)
+
+func (f hydratorOptionFunc) applyHydratorOption(r *Hydrator) {
+	f(r)
+}
+
+// WithOutput sets the output writer.
+func WithOutput(w io.Writer) HydratorOption {
+	return hydratorOptionFunc(func(r *Hydrator) {
+		r.output = w
+	})
+}
@@ -0,0 +1,194 @@
+package sitefallback_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/vanityrender/internal/service/sitefallback"
+	"go.nhat.io/vanityrender/internal/site"
+)
+
+func TestHydrator_Hydrate(t *testing.T) {
+	t.Parallel()
+
+	config := func() site.Site {
+		return site.Site{
+			Hostname: "go.nhat.io",
+			Repositories: []site.Repository{
+				{Name: "Contrib", Path: "contrib", RepositoryURL: "https://github.com/org/go-contrib"},
+				{Name: "Test", Path: "test", RepositoryURL: "https://github.com/org/go-test", Hidden: true, Ref: "main", Homepage: "https://test.example.com", Submodules: []string{"sub"}, Tags: []string{"testing"}, Category: "Testing", License: "MIT", DocsURLTemplate: "https://godoc.example.com/{module}", Params: map[string]any{"badge": "beta"}},
+			},
+		}
+	}
+
+	cached := site.Site{
+		Hostname: "go.nhat.io",
+		Repositories: []site.Repository{
+			{
+				Name:           "Old Test",
+				Path:           "test/v2",
+				RepositoryURL:  "https://github.com/org/go-test",
+				RepositoryName: "github.com/org/go-test",
+				LatestVersion:  "v2.1.0",
+				Modules:        []site.Module{{Path: "test/v2", ImportPrefix: "test"}},
+				Description:    "Read from the README",
+				License:        "Apache-2.0",
+				Params:         map[string]any{"badge": "alpha"},
+			},
+		},
+	}
+
+	hydrateContrib := func(s *site.Site) {
+		s.Repositories[0].RepositoryName = "github.com/org/go-contrib"
+		s.Repositories[0].LatestVersion = "v0.3.0"
+	}
+
+	testCases := []struct {
+		scenario       string
+		mockCache      site.Hydrator
+		mockUpstream   site.Hydrator
+		expectedResult func() site.Site
+		expectedError  string
+		expectedStale  bool
+	}{
+		{
+			scenario:  "upstream success",
+			mockCache: unexpectedHydrator(t),
+			mockUpstream: hydrateFunc(func(_ context.Context, s *site.Site) error {
+				hydrateContrib(s)
+
+				return nil
+			}),
+			expectedResult: func() site.Site {
+				s := config()
+				hydrateContrib(&s)
+
+				return s
+			},
+		},
+		{
+			scenario:  "upstream error without repository",
+			mockCache: unexpectedHydrator(t),
+			mockUpstream: hydrateFunc(func(context.Context, *site.Site) error {
+				return errors.New("upstream error")
+			}),
+			expectedResult: config,
+			expectedError:  "upstream error",
+		},
+		{
+			scenario: "cache error",
+			mockCache: hydrateFunc(func(context.Context, *site.Site) error {
+				return errors.New("cache error")
+			}),
+			mockUpstream: hydrateFunc(func(_ context.Context, s *site.Site) error {
+				hydrateContrib(s)
+
+				return site.NewRepositoryError(s.Repositories[1], errors.New("could not clone"))
+			}),
+			expectedResult: func() site.Site {
+				s := config()
+				hydrateContrib(&s)
+
+				return s
+			},
+			expectedError: "test: could not clone",
+		},
+		{
+			scenario: "repository is not in cache",
+			mockCache: hydrateFunc(func(context.Context, *site.Site) error {
+				return nil
+			}),
+			mockUpstream: hydrateFunc(func(_ context.Context, s *site.Site) error {
+				hydrateContrib(s)
+
+				return site.NewRepositoryError(s.Repositories[1], errors.New("could not clone"))
+			}),
+			expectedResult: func() site.Site {
+				s := config()
+				hydrateContrib(&s)
+
+				return s
+			},
+			expectedError: "test: could not clone",
+		},
+		{
+			scenario: "fallback",
+			mockCache: hydrateFunc(func(_ context.Context, s *site.Site) error {
+				*s = cached
+
+				return nil
+			}),
+			mockUpstream: hydrateFunc(func(_ context.Context, s *site.Site) error {
+				hydrateContrib(s)
+
+				return errors.Join(site.NewRepositoryError(s.Repositories[1], errors.New("could not clone")))
+			}),
+			expectedResult: func() site.Site {
+				s := config()
+				hydrateContrib(&s)
+
+				s.Repositories[1] = site.Repository{
+					Name:            "Test",
+					Path:            "test",
+					Hidden:          true,
+					Ref:             "main",
+					Homepage:        "https://test.example.com",
+					Submodules:      []string{"sub"},
+					RepositoryURL:   "https://github.com/org/go-test",
+					RepositoryName:  "github.com/org/go-test",
+					LatestVersion:   "v2.1.0",
+					Modules:         []site.Module{{Path: "test/v2", ImportPrefix: "test"}},
+					LatestPath:      "test/v2",
+					Description:     "Read from the README",
+					Tags:            []string{"testing"},
+					Category:        "Testing",
+					License:         "MIT",
+					DocsURLTemplate: "https://godoc.example.com/{module}",
+					Params:          map[string]any{"badge": "beta"},
+				}
+
+				return s
+			},
+			expectedError: "some repositories use stale data: test: could not clone",
+			expectedStale: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			actual := config()
+
+			err := sitefallback.NewHydrator(tc.mockCache, tc.mockUpstream).Hydrate(t.Context(), &actual)
+
+			assert.Equal(t, tc.expectedResult(), actual)
+			assert.Equal(t, tc.expectedStale, errors.Is(err, site.ErrStaleData))
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+type hydrateFunc func(ctx context.Context, s *site.Site) error
+
+func (f hydrateFunc) Hydrate(ctx context.Context, s *site.Site) error {
+	return f(ctx, s)
+}
+
+func unexpectedHydrator(t *testing.T) site.Hydrator {
+	t.Helper()
+
+	return hydrateFunc(func(context.Context, *site.Site) error {
+		t.Error("unexpected call")
+
+		return nil
+	})
+}
@@ -1,6 +1,7 @@
 package sitefragment
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -24,27 +25,27 @@ type Hydrator struct {
 }
 
 // Hydrate hydrates the site configuration.
-func (h *Hydrator) Hydrate(s *site.Site) error {
+func (h *Hydrator) Hydrate(ctx context.Context, s *site.Site) error {
 	if len(h.modules) == 0 {
-		return h.upstream.Hydrate(s)
+		return h.upstream.Hydrate(ctx, s)
 	}
 
 	originalRepos := repositoriesMap(s.Repositories)
 
-	if err := h.cache.Hydrate(s); err != nil {
+	if err := h.cache.Hydrate(ctx, s); err != nil {
 		if isCacheErrors(err) {
 			_, _ = fmt.Fprintln(h.output, color.HiRedString("Cache Error"), ":", err.Error()) //nolint: errcheck
 
-			return h.upstream.Hydrate(s)
+			return h.upstream.Hydrate(ctx, s)
 		}
 
 		return err // nolint: errcheck
 	}
 
-	return h.hydrateFragments(s, originalRepos)
+	return h.hydrateFragments(ctx, s, originalRepos)
 }
 
-func (h *Hydrator) hydrateFragments(s *site.Site, originalRepos map[string]site.Repository) error {
+func (h *Hydrator) hydrateFragments(ctx context.Context, s *site.Site, originalRepos map[string]site.Repository) error {
 	indexes := make(map[string]int, len(h.modules))
 	for _, m := range h.modules {
 		indexes[module.PathWithoutVersion(m)] = -1
@@ -69,7 +70,8 @@ func (h *Hydrator) hydrateFragments(s *site.Site, originalRepos map[string]site.
 		}
 	}
 
-	if err := h.upstream.Hydrate(&s2); err != nil {
+	err := h.upstream.Hydrate(ctx, &s2)
+	if err != nil && !errors.Is(err, site.ErrStaleData) {
 		return err // nolint: errcheck
 	}
 
@@ -78,7 +80,7 @@ func (h *Hydrator) hydrateFragments(s *site.Site, originalRepos map[string]site.
 		s.Repositories[i] = r
 	}
 
-	return nil
+	return err
 }
 
 // NewHydrator initiates a new site.Hydrator.
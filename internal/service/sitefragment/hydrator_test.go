@@ -1,7 +1,9 @@
 package sitefragment_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -422,7 +424,7 @@ func TestHydrator_Hydrate(t *testing.T) { // nolint: maintidx
 
 			h := sitefragment.NewHydrator(tc.mockCache(t), tc.mockUpstream(t), tc.modules)
 
-			err := h.Hydrate(&tc.input)
+			err := h.Hydrate(t.Context(), &tc.input)
 
 			assert.Equal(t, tc.expectedResult, tc.input)
 
@@ -435,6 +437,42 @@ func TestHydrator_Hydrate(t *testing.T) { // nolint: maintidx
 	}
 }
 
+func TestHydrator_Hydrate_StaleData(t *testing.T) {
+	t.Parallel()
+
+	cache := mockHydrateSuccess(func(_ *testing.T, s *site.Site) {
+		s.Repositories = []site.Repository{
+			{Name: "Test", Path: "test", LatestVersion: "v0.6.0"},
+			{Name: "Contrib", Path: "contrib", LatestVersion: "v0.1.0"},
+		}
+	})
+
+	upstream := hydrateFunc(func(s *site.Site) error {
+		s.Repositories[0].LatestVersion = "v0.2.0"
+
+		return fmt.Errorf("%w: %w", site.ErrStaleData, site.NewRepositoryError(s.Repositories[0], errors.New("could not clone")))
+	})
+
+	actual := site.Site{
+		Repositories: []site.Repository{
+			{Name: "Test", Path: "test"},
+			{Name: "Contrib", Path: "contrib"},
+		},
+	}
+
+	err := sitefragment.NewHydrator(cache(t), upstream, []string{"contrib"}).Hydrate(t.Context(), &actual)
+
+	expected := site.Site{
+		Repositories: []site.Repository{
+			{Name: "Test", Path: "test", LatestVersion: "v0.6.0"},
+			{Name: "Contrib", Path: "contrib", LatestVersion: "v0.2.0"},
+		},
+	}
+
+	assert.Equal(t, expected, actual)
+	assert.ErrorIs(t, err, site.ErrStaleData)
+}
+
 var nopHydrator = func(t *testing.T) site.Hydrator {
 	t.Helper()
 
@@ -447,7 +485,7 @@ var nopHydrator = func(t *testing.T) site.Hydrator {
 
 type hydrateFunc func(s *site.Site) error
 
-func (f hydrateFunc) Hydrate(s *site.Site) error {
+func (f hydrateFunc) Hydrate(_ context.Context, s *site.Site) error {
 	return f(s)
 }
 
@@ -0,0 +1,2 @@
+// Package siteinfo provides functionalities for describing the repositories with their README and LICENSE files.
+package siteinfo
@@ -0,0 +1,163 @@
+package siteinfo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/fatih/color"
+	gogit "github.com/go-git/go-git/v5"
+
+	"go.nhat.io/vanityrender/internal/git"
+	"go.nhat.io/vanityrender/internal/module"
+	"go.nhat.io/vanityrender/internal/repoinfo"
+	"go.nhat.io/vanityrender/internal/site"
+	"go.nhat.io/vanityrender/internal/vcs"
+)
+
+var _ site.Hydrator = (*Hydrator)(nil)
+
+// Hydrator is a site.Hydrator that fills the description and the license of the git repositories that do not configure
+// them, from their README and LICENSE files at the configured ref, after the upstream hydrator. The repositories are
+// cloned with the cloner, so a cached cloner reuses the clones of the module finder.
+type Hydrator struct {
+	upstream site.Hydrator
+	cloner   git.Cloner
+
+	cloneTimeout time.Duration
+	output       io.Writer
+}
+
+// Hydrate hydrates the site configuration. The description and the license are optional, so a repository that could
+// not be read is reported but does not fail the hydration. When the upstream falls back to the previously published data
+// of some repositories, the other repositories are still hydrated, and the failed ones are not cloned again.
+func (h *Hydrator) Hydrate(ctx context.Context, s *site.Site) error {
+	err := h.upstream.Hydrate(ctx, s)
+	if err != nil && !errors.Is(err, site.ErrStaleData) {
+		return err
+	}
+
+	failed := make(map[string]struct{})
+
+	for _, f := range site.RepositoryErrors(err) {
+		failed[module.PathWithoutVersion(f.Path)] = struct{}{}
+	}
+
+	for i := range s.Repositories {
+		r := &s.Repositories[i]
+
+		if !vcs.IsGit(r.VCS) || len(r.Modules) == 0 || (len(r.Description) > 0 && len(r.License) > 0) {
+			continue
+		}
+
+		if _, ok := failed[module.PathWithoutVersion(r.Path)]; ok {
+			continue
+		}
+
+		if infoErr := h.hydrateRepository(ctx, r); infoErr != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+
+			_, _ = fmt.Fprintln(h.output, color.HiRedString("Info Error"), ":", r.RepositoryURL, infoErr) //nolint: errcheck
+		}
+	}
+
+	return err
+}
+
+func (h *Hydrator) hydrateRepository(ctx context.Context, r *site.Repository) error {
+	if timeout := h.timeout(r); timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	_, gitRepo, err := h.cloner.Clone(ctx, r.RepositoryURL, r.Ref)
+	if err != nil {
+		return fmt.Errorf("could not clone repository: %w", err)
+	}
+
+	if len(r.Description) == 0 {
+		if r.Description, err = readDescription(gitRepo); err != nil {
+			return err
+		}
+	}
+
+	if len(r.License) == 0 {
+		if r.License, err = readLicense(gitRepo); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (h *Hydrator) timeout(r *site.Repository) time.Duration {
+	if r.CloneTimeout > 0 {
+		return r.CloneTimeout
+	}
+
+	return h.cloneTimeout
+}
+
+// NewHydrator initiates a new site.Hydrator.
+func NewHydrator(upstream site.Hydrator, cloner git.Cloner, opts ...HydratorOption) *Hydrator {
+	h := &Hydrator{
+		upstream: upstream,
+		cloner:   cloner,
+		output:   io.Discard,
+	}
+
+	for _, o := range opts {
+		o.applyHydratorOption(h)
+	}
+
+	return h
+}
+
+func readDescription(r *gogit.Repository) (string, error) {
+	_, readme, err := git.ReadHeadFile(r, ".", repoinfo.ReadmeRank)
+	if err != nil {
+		return "", fmt.Errorf("could not read readme: %w", err)
+	}
+
+	return repoinfo.Description(readme), nil
+}
+
+func readLicense(r *gogit.Repository) (string, error) {
+	_, license, err := git.ReadHeadFile(r, ".", repoinfo.LicenseRank)
+	if err != nil {
+		return "", fmt.Errorf("could not read license: %w", err)
+	}
+
+	return repoinfo.License(license), nil
+}
+
+// HydratorOption is an option to configure Hydrator.
+type HydratorOption interface {
+	applyHydratorOption(r *Hydrator)
+}
+
+type hydratorOptionFunc func(r *Hydrator)
+
+func (f hydratorOptionFunc) applyHydratorOption(r *Hydrator) {
+	f(r)
+}
+
+// WithOutput sets the output writer.
+func WithOutput(w io.Writer) HydratorOption {
+	return hydratorOptionFunc(func(r *Hydrator) {
+		r.output = w
+	})
+}
+
+// WithCloneTimeout sets the default timeout for cloning a repository. Zero means no timeout.
+func WithCloneTimeout(d time.Duration) HydratorOption {
+	return hydratorOptionFunc(func(r *Hydrator) {
+		r.cloneTimeout = d
+	})
+}
@@ -0,0 +1,204 @@
+package siteinfo_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/vanityrender/internal/git"
+	"go.nhat.io/vanityrender/internal/service/sitefallback"
+	"go.nhat.io/vanityrender/internal/service/siteinfo"
+	"go.nhat.io/vanityrender/internal/site"
+)
+
+func TestHydrator_Hydrate(t *testing.T) {
+	t.Parallel()
+
+	repo := mockRepository(t, map[string]string{
+		"README.md": "# Lib\n\nA library for [examples](https://example.com).\n",
+		"LICENSE":   "MIT License\n\nPermission is hereby granted, free of charge, to any person\n",
+	})
+
+	var cloned []string
+
+	cloner := git.ClonerFunc(func(_ context.Context, url, _ string) (string, *gogit.Repository, error) {
+		cloned = append(cloned, url)
+
+		if url != "https://github.com/example/lib" {
+			return "", nil, errors.New("repository not found") //nolint: err113
+		}
+
+		return "", repo, nil
+	})
+
+	modules := []site.Module{{Path: "lib"}}
+
+	s := site.Site{
+		Hostname: "example.com",
+		Repositories: []site.Repository{
+			{Path: "lib", RepositoryURL: "https://github.com/example/lib", Modules: modules},
+			{Path: "configured", RepositoryURL: "https://github.com/example/lib", Modules: modules, Description: "Configured", License: "Apache-2.0"},
+			{Path: "partial", RepositoryURL: "https://github.com/example/lib", Modules: modules, License: "BSD-3-Clause"},
+			{Path: "unavailable", RepositoryURL: "https://github.com/example/unavailable", Modules: modules},
+			{Path: "not-hydrated", RepositoryURL: "https://github.com/example/not-hydrated"},
+			{Path: "hg", VCS: "hg", RepositoryURL: "https://hg.example.com/hg", Modules: modules},
+		},
+	}
+
+	out := new(bytes.Buffer)
+
+	err := siteinfo.NewHydrator(site.Hydrators{}, cloner, siteinfo.WithOutput(out)).Hydrate(t.Context(), &s)
+	require.NoError(t, err)
+
+	expected := []site.Repository{
+		{Path: "lib", RepositoryURL: "https://github.com/example/lib", Modules: modules, Description: "A library for examples.", License: "MIT"},
+		{Path: "configured", RepositoryURL: "https://github.com/example/lib", Modules: modules, Description: "Configured", License: "Apache-2.0"},
+		{Path: "partial", RepositoryURL: "https://github.com/example/lib", Modules: modules, Description: "A library for examples.", License: "BSD-3-Clause"},
+		{Path: "unavailable", RepositoryURL: "https://github.com/example/unavailable", Modules: modules},
+		{Path: "not-hydrated", RepositoryURL: "https://github.com/example/not-hydrated"},
+		{Path: "hg", VCS: "hg", RepositoryURL: "https://hg.example.com/hg", Modules: modules},
+	}
+
+	assert.Equal(t, expected, s.Repositories)
+	assert.Equal(t, []string{"https://github.com/example/lib", "https://github.com/example/lib", "https://github.com/example/unavailable"}, cloned)
+	assert.Contains(t, out.String(), "https://github.com/example/unavailable could not clone repository: repository not found")
+}
+
+func TestHydrator_Hydrate_Canceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+
+	cloner := git.ClonerFunc(func(context.Context, string, string) (string, *gogit.Repository, error) {
+		cancel()
+
+		return "", nil, context.Canceled
+	})
+
+	s := site.Site{
+		Repositories: []site.Repository{
+			{Path: "lib", RepositoryURL: "https://github.com/example/lib", Modules: []site.Module{{Path: "lib"}}},
+		},
+	}
+
+	err := siteinfo.NewHydrator(site.Hydrators{}, cloner).Hydrate(ctx, &s)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestHydrator_Hydrate_Fallback(t *testing.T) {
+	t.Parallel()
+
+	repo := mockRepository(t, map[string]string{
+		"README.md": "# Lib\n\nA library for examples.\n",
+		"LICENSE":   "MIT License\n\nPermission is hereby granted, free of charge, to any person\n",
+	})
+
+	var cloned []string
+
+	cloner := git.ClonerFunc(func(_ context.Context, url, _ string) (string, *gogit.Repository, error) {
+		cloned = append(cloned, url)
+
+		return "", repo, nil
+	})
+
+	modules := []site.Module{{Path: "lib"}}
+
+	// The second repository could not be fetched, and is restored from its previously published data.
+	upstream := hydrateFunc(func(_ context.Context, s *site.Site) error {
+		s.Repositories[0].Modules = modules
+
+		return site.NewRepositoryError(s.Repositories[1], errors.New("could not clone")) //nolint: err113
+	})
+
+	cache := hydrateFunc(func(_ context.Context, s *site.Site) error {
+		s.Repositories = []site.Repository{
+			{Path: "unavailable", RepositoryURL: "https://github.com/example/unavailable", Modules: modules},
+		}
+
+		return nil
+	})
+
+	s := site.Site{
+		Hostname: "example.com",
+		Repositories: []site.Repository{
+			{Path: "lib", RepositoryURL: "https://github.com/example/lib"},
+			{Path: "unavailable", RepositoryURL: "https://github.com/example/unavailable"},
+		},
+	}
+
+	h := siteinfo.NewHydrator(sitefallback.NewHydrator(cache, upstream), cloner)
+
+	err := h.Hydrate(t.Context(), &s)
+	require.ErrorIs(t, err, site.ErrStaleData)
+
+	expected := []site.Repository{
+		{Path: "lib", RepositoryURL: "https://github.com/example/lib", Modules: modules, Description: "A library for examples.", License: "MIT"},
+		{Path: "unavailable", RepositoryURL: "https://github.com/example/unavailable", Modules: modules},
+	}
+
+	assert.Equal(t, expected, s.Repositories)
+	assert.Equal(t, []string{"https://github.com/example/lib"}, cloned)
+}
+
+func TestHydrator_Hydrate_UpstreamError(t *testing.T) {
+	t.Parallel()
+
+	cloner := git.ClonerFunc(func(context.Context, string, string) (string, *gogit.Repository, error) {
+		t.Fatal("unexpected clone")
+
+		return "", nil, nil
+	})
+
+	upstream := hydrateFunc(func(context.Context, *site.Site) error {
+		return errors.New("upstream error") //nolint: err113
+	})
+
+	s := site.Site{
+		Repositories: []site.Repository{
+			{Path: "lib", RepositoryURL: "https://github.com/example/lib", Modules: []site.Module{{Path: "lib"}}},
+		},
+	}
+
+	err := siteinfo.NewHydrator(upstream, cloner).Hydrate(t.Context(), &s)
+	require.EqualError(t, err, "upstream error")
+}
+
+type hydrateFunc func(ctx context.Context, s *site.Site) error
+
+func (f hydrateFunc) Hydrate(ctx context.Context, s *site.Site) error {
+	return f(ctx, s)
+}
+
+func mockRepository(t *testing.T, files map[string]string) *gogit.Repository {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	r, err := gogit.PlainInit(dir, false)
+	require.NoError(t, err)
+
+	for name, data := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(data), 0o600))
+	}
+
+	w, err := r.Worktree()
+	require.NoError(t, err)
+
+	require.NoError(t, w.AddGlob("."))
+
+	sig := &object.Signature{Name: "John Doe", Email: "john.doe@example.com", When: time.Now()}
+
+	_, err = w.Commit("Initial commit", &gogit.CommitOptions{Author: sig, Committer: sig})
+	require.NoError(t, err)
+
+	return r
+}
@@ -0,0 +1,2 @@
+// Package siteproxy provides functionalities for publishing the modules in a static Go module proxy next to the pages.
+package siteproxy
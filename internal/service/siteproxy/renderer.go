@@ -0,0 +1,252 @@
+package siteproxy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	gogit "github.com/go-git/go-git/v5"
+	"golang.org/x/mod/modfile"
+
+	"go.nhat.io/vanityrender/internal/git"
+	"go.nhat.io/vanityrender/internal/goproxy"
+	"go.nhat.io/vanityrender/internal/module"
+	"go.nhat.io/vanityrender/internal/site"
+	"go.nhat.io/vanityrender/internal/vcs"
+)
+
+var _ site.Renderder = (*Renderder)(nil)
+
+// Renderder is a site.Renderder that writes the versions of the git modules in a static Go module proxy, and points
+// the go-import tags of the modules to it, so they can be fetched even when the git host is down.
+type Renderder struct {
+	upstream site.Renderder
+	cloner   git.Cloner
+
+	outputDir    string
+	proxyDir     string
+	proxyURL     string
+	cloneTimeout time.Duration
+	output       io.Writer
+}
+
+// Render renders the site.
+func (r *Renderder) Render(ctx context.Context, s site.Site) error {
+	proxyURL := r.proxyURL
+	if len(proxyURL) == 0 {
+		proxyURL = fmt.Sprintf("https://%s/%s", s.Hostname, r.proxyDir)
+	}
+
+	w := goproxy.NewWriter(filepath.Join(r.outputDir, filepath.FromSlash(r.proxyDir)))
+
+	s2 := s
+	s2.Repositories = make([]site.Repository, len(s.Repositories))
+
+	for i, repo := range s.Repositories {
+		repo.Modules = append([]site.Module(nil), repo.Modules...)
+
+		if err := r.publishRepository(ctx, w, s.Hostname, proxyURL, &repo); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+
+			_, _ = fmt.Fprintln(r.output, color.HiRedString("Proxy Error"), ":", repo.RepositoryURL, err) //nolint: errcheck
+
+			repo.Modules = s.Repositories[i].Modules
+		}
+
+		s2.Repositories[i] = repo
+	}
+
+	return r.upstream.Render(ctx, s2)
+}
+
+// publishRepository writes the modules of the repository into the proxy. The modules restored from the previously
+// published data already point to the proxy, and are only written again when their files are not in the output
+// directory, e.g. on a fresh checkout. The repositories are cloned with the cloner, so a cached cloner reuses the clones
+// of the hydration.
+func (r *Renderder) publishRepository(ctx context.Context, w *goproxy.Writer, host, proxyURL string, repo *site.Repository) error {
+	publishable := func(m site.Module) bool {
+		return isPublishable(w, host, proxyURL, m)
+	}
+
+	if !vcs.IsGit(repo.VCS) || !slices.ContainsFunc(repo.Modules, publishable) {
+		return nil
+	}
+
+	if timeout := r.timeout(repo); timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	_, gitRepo, err := r.cloner.Clone(ctx, repo.RepositoryURL, repo.Ref)
+	if err != nil {
+		return fmt.Errorf("could not clone repository: %w", err)
+	}
+
+	tags, err := repositoryVersions(gitRepo, repo.AllTags)
+	if err != nil {
+		return err
+	}
+
+	for i, m := range repo.Modules {
+		if !publishable(m) {
+			continue
+		}
+
+		dir, ok, err := r.publishModule(w, gitRepo, tags, host, repo.Path, m)
+		if err != nil {
+			return err
+		}
+
+		// The restored modules already point to the proxy.
+		if ok && m.VCS == vcs.Git {
+			repo.Modules[i] = proxyModule(m, proxyURL, dir)
+		}
+	}
+
+	return nil
+}
+
+// publishModule writes the versions of the module, and returns the directory of its latest version in the repository.
+func (r *Renderder) publishModule(w *goproxy.Writer, gitRepo *gogit.Repository, tags []string, host, repoPath string, m site.Module) (string, bool, error) {
+	modPath := path.Join(host, m.Path)
+
+	p := module.Path(strings.TrimPrefix(strings.TrimPrefix(m.Path, repoPath), "/"))
+	if p == "" {
+		p = "."
+	}
+
+	versions, err := git.ModuleVersions(gitRepo, tags, p)
+	if err != nil {
+		return "", false, err
+	}
+
+	published := make([]string, 0, len(versions))
+	dir := ""
+
+	for _, v := range versions {
+		// The go command rejects the versions whose go.mod files declare another module path, e.g. after a rename.
+		if v.GoMod != nil && modfile.ModulePath(v.GoMod) != modPath {
+			continue
+		}
+
+		err := w.WriteVersion(modPath, goproxy.Info{Version: v.Version, Time: v.Time}, v.GoMod, v.Files)
+		if err != nil {
+			return "", false, fmt.Errorf("could not write %s@%s: %w", modPath, v.Version, err)
+		}
+
+		published = append(published, v.Version)
+		dir = v.Dir
+	}
+
+	if len(published) == 0 {
+		return "", false, nil
+	}
+
+	if err := w.WriteList(modPath, published); err != nil {
+		return "", false, fmt.Errorf("could not write %s: %w", modPath, err)
+	}
+
+	_, _ = fmt.Fprintln(r.output, color.HiGreenString("Proxy"), ":", modPath, published[len(published)-1]) //nolint: errcheck
+
+	return dir, true, nil
+}
+
+// NewRenderder initiates a new Renderder that writes the proxy into the proxyDir subdirectory of the output directory.
+func NewRenderder(upstream site.Renderder, cloner git.Cloner, outputDir, proxyDir string, opts ...RendererOption) *Renderder {
+	r := &Renderder{
+		upstream:  upstream,
+		cloner:    cloner,
+		outputDir: outputDir,
+		proxyDir:  strings.Trim(proxyDir, "/"),
+		output:    io.Discard,
+	}
+
+	for _, o := range opts {
+		o.applyRendererOption(r)
+	}
+
+	return r
+}
+
+func (r *Renderder) timeout(repo *site.Repository) time.Duration {
+	if repo.CloneTimeout > 0 {
+		return repo.CloneTimeout
+	}
+
+	return r.cloneTimeout
+}
+
+// isPublishable returns true if the module is fetched from git, or from the proxy after being restored from the
+// previously published data but its files are not written yet.
+func isPublishable(w *goproxy.Writer, host, proxyURL string, m site.Module) bool {
+	if m.VCS == vcs.Git {
+		return true
+	}
+
+	return m.VCS == vcs.Mod && m.RepositoryURL == proxyURL && !w.HasList(path.Join(host, m.Path))
+}
+
+func repositoryVersions(r *gogit.Repository, allTags bool) ([]string, error) {
+	if allTags {
+		return git.AllVersions(r) // nolint: wrapcheck
+	}
+
+	return git.Versions(r) // nolint: wrapcheck
+}
+
+// proxyModule points the go-import tag of the module to the proxy. The import prefix becomes the module path, so the
+// go-source tag is relative to the module directory.
+func proxyModule(m site.Module, proxyURL, dir string) site.Module {
+	m.ImportPrefix = m.Path
+	m.VCS = vcs.Mod
+	m.RepositoryURL = proxyURL
+
+	if dir != "." {
+		m.DirectoryURL = strings.Replace(m.DirectoryURL, "{/dir}", "/"+dir+"{/dir}", 1)
+		m.FileURL = strings.Replace(m.FileURL, "{/dir}", "/"+dir+"{/dir}", 1)
+	}
+
+	return m
+}
+
+// RendererOption is an option to configure Renderder.
+type RendererOption interface {
+	applyRendererOption(r *Renderder)
+}
+
+type rendererOptionFunc func(r *Renderder)
+
+func (f rendererOptionFunc) applyRendererOption(r *Renderder) {
+	f(r)
+}
+
+// WithProxyURL sets the URL of the proxy in the go-import tags, by default https://<host>/<proxyDir>.
+func WithProxyURL(url string) RendererOption {
+	return rendererOptionFunc(func(r *Renderder) {
+		r.proxyURL = strings.TrimSuffix(url, "/")
+	})
+}
+
+// WithCloneTimeout sets the default timeout for cloning a repository. Zero means no timeout.
+func WithCloneTimeout(d time.Duration) RendererOption {
+	return rendererOptionFunc(func(r *Renderder) {
+		r.cloneTimeout = d
+	})
+}
+
+// WithOutput sets the output writer.
+func WithOutput(w io.Writer) RendererOption {
+	return rendererOptionFunc(func(r *Renderder) {
+		r.output = w
+	})
+}
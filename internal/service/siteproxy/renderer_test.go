@@ -0,0 +1,388 @@
+package siteproxy_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/vanityrender/internal/git"
+	"go.nhat.io/vanityrender/internal/goproxy"
+	"go.nhat.io/vanityrender/internal/module"
+	"go.nhat.io/vanityrender/internal/service/siteproxy"
+	"go.nhat.io/vanityrender/internal/site"
+)
+
+func TestRenderder_Render(t *testing.T) {
+	t.Parallel()
+
+	repo := mockRepository(t)
+	cloner := git.ClonerFunc(func(_ context.Context, url, _ string) (string, *gogit.Repository, error) {
+		if url != "https://github.com/example/lib" {
+			return "", nil, errors.New("repository not found") //nolint: err113
+		}
+
+		return "", repo, nil
+	})
+
+	s := site.Site{
+		Hostname: "example.com",
+		Repositories: []site.Repository{
+			{
+				Name:          "Lib",
+				Path:          "lib",
+				RepositoryURL: "https://github.com/example/lib",
+				Modules: []site.Module{
+					gitModule("lib", "lib"),
+					gitModule("lib/contrib", "lib"),
+					gitModule("lib/v2", "lib"),
+				},
+			},
+			{
+				Name:          "Cached",
+				Path:          "cached",
+				RepositoryURL: "https://github.com/example/cached",
+				Modules: []site.Module{
+					{Path: "cached", ImportPrefix: "cached", VCS: "mod", RepositoryURL: "https://example.com/mod"},
+				},
+			},
+			{
+				Name:          "Unavailable",
+				Path:          "unavailable",
+				RepositoryURL: "https://github.com/example/unavailable",
+				Modules: []site.Module{
+					gitModule("unavailable", "unavailable"),
+				},
+			},
+			{
+				Name:          "Mercurial",
+				Path:          "hg",
+				VCS:           "hg",
+				RepositoryURL: "https://hg.example.com/hg",
+				Modules: []site.Module{
+					{Path: "hg", ImportPrefix: "hg", VCS: "hg", RepositoryURL: "https://hg.example.com/hg"},
+				},
+			},
+		},
+	}
+
+	var rendered site.Site
+
+	upstream := renderderFunc(func(_ context.Context, s site.Site) error {
+		rendered = s
+
+		return nil
+	})
+
+	outputDir := t.TempDir()
+
+	r := siteproxy.NewRenderder(upstream, cloner, outputDir, "/mod/")
+
+	err := r.Render(t.Context(), s)
+	require.NoError(t, err)
+
+	expected := []site.Module{
+		{
+			Path:          "lib",
+			ImportPrefix:  "lib",
+			VCS:           "mod",
+			RepositoryURL: "https://example.com/mod",
+			HomeURL:       "https://github.com/example/lib",
+			DirectoryURL:  "https://github.com/example/lib/tree/master{/dir}",
+			FileURL:       "https://github.com/example/lib/blob/master{/dir}/{file}#L{line}",
+		},
+		{
+			Path:          "lib/contrib",
+			ImportPrefix:  "lib/contrib",
+			VCS:           "mod",
+			RepositoryURL: "https://example.com/mod",
+			HomeURL:       "https://github.com/example/lib",
+			DirectoryURL:  "https://github.com/example/lib/tree/master/contrib{/dir}",
+			FileURL:       "https://github.com/example/lib/blob/master/contrib{/dir}/{file}#L{line}",
+		},
+		{
+			Path:          "lib/v2",
+			ImportPrefix:  "lib/v2",
+			VCS:           "mod",
+			RepositoryURL: "https://example.com/mod",
+			HomeURL:       "https://github.com/example/lib",
+			DirectoryURL:  "https://github.com/example/lib/tree/master/v2{/dir}",
+			FileURL:       "https://github.com/example/lib/blob/master/v2{/dir}/{file}#L{line}",
+		},
+	}
+
+	assert.Equal(t, expected, rendered.Repositories[0].Modules)
+	assert.Equal(t, s.Repositories[1:], rendered.Repositories[1:])
+
+	// The original site is not modified.
+	assert.Equal(t, "git", s.Repositories[0].Modules[0].VCS)
+
+	// The modules are served by the proxy.
+	f := goproxy.NewFinder(goproxy.WithProxy("file://" + filepath.Join(outputDir, "mod")))
+
+	actual, err := f.Find(t.Context(), module.Source{ImportPath: "example.com/lib", Submodules: []string{"contrib"}})
+	require.NoError(t, err)
+
+	expectedVersions := map[module.Path]module.Version{
+		".":       module.NewVersion(1, 0, 0),
+		"contrib": module.NewVersion(0, 1, 0),
+		"v2":      module.NewVersion(2, 0, 0),
+	}
+
+	assert.Equal(t, expectedVersions, actual)
+
+	// The versions of another module path are not published.
+	list, err := os.ReadFile(filepath.Join(outputDir, "mod", "example.com", "lib", "@v", "list")) //nolint: gosec
+	require.NoError(t, err)
+
+	assert.Equal(t, "v0.1.0\nv1.0.0\n", string(list))
+}
+
+func TestRenderder_Render_Restored(t *testing.T) {
+	t.Parallel()
+
+	repo := mockRepository(t)
+	cloner := git.ClonerFunc(func(context.Context, string, string) (string, *gogit.Repository, error) {
+		return "", repo, nil
+	})
+
+	restored := site.Module{
+		Path:          "lib",
+		ImportPrefix:  "lib",
+		VCS:           "mod",
+		RepositoryURL: "https://example.com/mod",
+		HomeURL:       "https://github.com/example/lib",
+	}
+
+	s := site.Site{
+		Hostname: "example.com",
+		Repositories: []site.Repository{
+			{
+				Name:          "Lib",
+				Path:          "lib",
+				RepositoryURL: "https://github.com/example/lib",
+				Modules:       []site.Module{restored},
+			},
+		},
+	}
+
+	var rendered site.Site
+
+	upstream := renderderFunc(func(_ context.Context, s site.Site) error {
+		rendered = s
+
+		return nil
+	})
+
+	// The output directory does not have the proxy files of the restored modules.
+	outputDir := t.TempDir()
+
+	err := siteproxy.NewRenderder(upstream, cloner, outputDir, "mod").Render(t.Context(), s)
+	require.NoError(t, err)
+
+	assert.Equal(t, []site.Module{restored}, rendered.Repositories[0].Modules)
+
+	list, err := os.ReadFile(filepath.Join(outputDir, "mod", "example.com", "lib", "@v", "list")) //nolint: gosec
+	require.NoError(t, err)
+
+	assert.Equal(t, "v0.1.0\nv1.0.0\n", string(list))
+}
+
+func TestRenderder_Render_RestoredAlreadyPublished(t *testing.T) {
+	t.Parallel()
+
+	cloner := git.ClonerFunc(func(context.Context, string, string) (string, *gogit.Repository, error) {
+		t.Fatal("unexpected clone")
+
+		return "", nil, nil
+	})
+
+	outputDir := t.TempDir()
+
+	writeFile(t, filepath.Join(outputDir, "mod", "example.com", "lib", "@v", "list"), "v1.0.0\n")
+
+	s := site.Site{
+		Hostname: "example.com",
+		Repositories: []site.Repository{
+			{
+				Name:          "Lib",
+				Path:          "lib",
+				RepositoryURL: "https://github.com/example/lib",
+				Modules: []site.Module{
+					{Path: "lib", ImportPrefix: "lib", VCS: "mod", RepositoryURL: "https://example.com/mod"},
+				},
+			},
+		},
+	}
+
+	var rendered site.Site
+
+	upstream := renderderFunc(func(_ context.Context, s site.Site) error {
+		rendered = s
+
+		return nil
+	})
+
+	err := siteproxy.NewRenderder(upstream, cloner, outputDir, "mod").Render(t.Context(), s)
+	require.NoError(t, err)
+
+	assert.Equal(t, s.Repositories, rendered.Repositories)
+}
+
+func TestRenderder_Render_CloneTimeout(t *testing.T) {
+	t.Parallel()
+
+	var deadlines []bool
+
+	cloner := git.ClonerFunc(func(ctx context.Context, _, _ string) (string, *gogit.Repository, error) {
+		_, ok := ctx.Deadline()
+		deadlines = append(deadlines, ok)
+
+		return "", nil, errors.New("repository not found") //nolint: err113
+	})
+
+	s := site.Site{
+		Hostname: "example.com",
+		Repositories: []site.Repository{
+			{Name: "Lib", Path: "lib", RepositoryURL: "https://github.com/example/lib", Modules: []site.Module{gitModule("lib", "lib")}},
+		},
+	}
+
+	upstream := renderderFunc(func(context.Context, site.Site) error {
+		return nil
+	})
+
+	err := siteproxy.NewRenderder(upstream, cloner, t.TempDir(), "mod").Render(t.Context(), s)
+	require.NoError(t, err)
+
+	err = siteproxy.NewRenderder(upstream, cloner, t.TempDir(), "mod", siteproxy.WithCloneTimeout(time.Minute)).Render(t.Context(), s)
+	require.NoError(t, err)
+
+	assert.Equal(t, []bool{false, true}, deadlines)
+}
+
+func TestRenderder_Render_ProxyURL(t *testing.T) {
+	t.Parallel()
+
+	repo := mockRepository(t)
+	cloner := git.ClonerFunc(func(context.Context, string, string) (string, *gogit.Repository, error) {
+		return "", repo, nil
+	})
+
+	s := site.Site{
+		Hostname: "example.com",
+		Repositories: []site.Repository{
+			{
+				Name:          "Lib",
+				Path:          "lib",
+				RepositoryURL: "https://github.com/example/lib",
+				Modules: []site.Module{
+					gitModule("lib", "lib"),
+					// Restored with the default proxy URL, which is another proxy now.
+					{Path: "lib/v2", ImportPrefix: "lib/v2", VCS: "mod", RepositoryURL: "https://example.com/mod"},
+				},
+			},
+		},
+	}
+
+	var rendered site.Site
+
+	upstream := renderderFunc(func(_ context.Context, s site.Site) error {
+		rendered = s
+
+		return nil
+	})
+
+	outputDir := t.TempDir()
+
+	r := siteproxy.NewRenderder(upstream, cloner, outputDir, "mod", siteproxy.WithProxyURL("http://localhost:8080/mod/"))
+
+	err := r.Render(t.Context(), s)
+	require.NoError(t, err)
+
+	actual := rendered.Repositories[0].Modules
+
+	assert.Equal(t, "mod", actual[0].VCS)
+	assert.Equal(t, "http://localhost:8080/mod", actual[0].RepositoryURL)
+	assert.Equal(t, s.Repositories[0].Modules[1], actual[1])
+
+	assert.FileExists(t, filepath.Join(outputDir, "mod", "example.com", "lib", "@v", "list"))
+	assert.NoFileExists(t, filepath.Join(outputDir, "mod", "example.com", "lib", "v2", "@v", "list"))
+}
+
+type renderderFunc func(ctx context.Context, s site.Site) error
+
+func (f renderderFunc) Render(ctx context.Context, s site.Site) error {
+	return f(ctx, s)
+}
+
+func gitModule(p, prefix string) site.Module {
+	return site.Module{
+		Path:          p,
+		ImportPrefix:  prefix,
+		VCS:           "git",
+		RepositoryURL: "https://github.com/example/" + prefix,
+		HomeURL:       "https://github.com/example/" + prefix,
+		DirectoryURL:  "https://github.com/example/" + prefix + "/tree/master{/dir}",
+		FileURL:       "https://github.com/example/" + prefix + "/blob/master{/dir}/{file}#L{line}",
+	}
+}
+
+func mockRepository(t *testing.T) *gogit.Repository {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	r, err := gogit.PlainInit(dir, false)
+	require.NoError(t, err)
+
+	// v0.1.0 has no go.mod file.
+	writeFile(t, filepath.Join(dir, "lib.go"), "package lib\n")
+	commitAndTag(t, r, "v0.1.0")
+
+	// v0.2.0 declares another module path.
+	writeFile(t, filepath.Join(dir, "go.mod"), "module github.com/example/lib\n")
+	commitAndTag(t, r, "v0.2.0")
+
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/lib\n")
+	writeFile(t, filepath.Join(dir, "contrib", "go.mod"), "module example.com/lib/contrib\n")
+	commitAndTag(t, r, "v1.0.0", "contrib/v0.1.0")
+
+	writeFile(t, filepath.Join(dir, "v2", "go.mod"), "module example.com/lib/v2\n")
+	commitAndTag(t, r, "v2.0.0")
+
+	return r
+}
+
+func commitAndTag(t *testing.T, r *gogit.Repository, tags ...string) {
+	t.Helper()
+
+	w, err := r.Worktree()
+	require.NoError(t, err)
+
+	require.NoError(t, w.AddGlob("."))
+
+	sig := &object.Signature{Name: "John Doe", Email: "john.doe@example.com", When: time.Now()}
+
+	h, err := w.Commit(tags[0], &gogit.CommitOptions{Author: sig, Committer: sig})
+	require.NoError(t, err)
+
+	for _, tag := range tags {
+		_, err := r.CreateTag(tag, h, nil)
+		require.NoError(t, err)
+	}
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+}
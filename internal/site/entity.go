@@ -1,5 +1,7 @@
 package site
 
+import "time"
+
 // Site is the site configuration.
 type Site struct {
 	PageTitle       string       `json:"page_title"`
@@ -7,6 +9,22 @@ type Site struct {
 	Hostname        string       `json:"hostname"`
 	SourceURL       string       `json:"source_url"`
 	Repositories    []Repository `json:"repositories"`
+	// Categories are the order of the categories on the homepage, the other categories follow in the order of their
+	// first repositories.
+	Categories []string `json:"categories,omitempty"`
+	// Search shows a box to filter the repositories on the homepage.
+	Search bool `json:"search,omitempty"`
+	// DocsURLTemplate is the URL of the documentation of a module, with the {module}, {path} and {version} placeholders.
+	// The default is https://pkg.go.dev/{module}.
+	DocsURLTemplate string `json:"docs_url_template,omitempty"`
+	// DisableRedirect keeps the visitors on the module pages instead of redirecting them to the documentation.
+	DisableRedirect bool `json:"disable_redirect,omitempty"`
+	// SelfContained serves the stylesheets, the scripts and the images from the output directory instead of the CDNs.
+	SelfContained bool `json:"self_contained,omitempty"`
+	// NoInlineScripts loads the scripts of the pages from a file, for a Content-Security-Policy without 'unsafe-inline'.
+	NoInlineScripts bool `json:"no_inline_scripts,omitempty"`
+	// Params are the user-defined variables of all the pages, e.g. {{params.logoURL}}.
+	Params map[string]any `json:"params,omitempty"`
 }
 
 // Repository is a repository configuration.
@@ -15,11 +33,32 @@ type Repository struct {
 	Path           string   `json:"path"`
 	Deprecated     string   `json:"deprecated"`
 	Hidden         bool     `json:"hidden"`
+	VCS            string   `json:"vcs"`
 	RepositoryURL  string   `json:"repository_url"`
 	RepositoryName string   `json:"repository_name"`
 	Ref            string   `json:"ref"`
 	LatestVersion  string   `json:"latest_version"`
 	Modules        []Module `json:"modules"`
+	// LatestPath is the path of the latest major version of the root module, e.g. mock/v2, if it is not Path.
+	LatestPath string `json:"latest_path,omitempty"`
+	// Description is read from the first paragraph of the README file if it is not configured.
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Category    string   `json:"category,omitempty"`
+	Homepage    string   `json:"homepage,omitempty"`
+	DocsURL     string   `json:"docs_url,omitempty"`
+	// DocsURLTemplate overrides the documentation URL template of the site for the modules of the repository.
+	DocsURLTemplate string `json:"docs_url_template,omitempty"`
+	// License is the SPDX identifier of the license, detected from the LICENSE file if it is not configured.
+	License string `json:"license,omitempty"`
+	// Params are the user-defined variables of the repository and its module pages, merged over the ones of the site.
+	Params map[string]any `json:"params,omitempty"`
+
+	CloneTimeout time.Duration `json:"-"`
+	Submodules   []string      `json:"-"`
+	AllTags      bool          `json:"-"`
+	Include      []string      `json:"-"`
+	Exclude      []string      `json:"-"`
 }
 
 // Module is a module configuration.
@@ -31,4 +70,10 @@ type Module struct {
 	HomeURL       string `json:"home_url"`
 	DirectoryURL  string `json:"directory_url"`
 	FileURL       string `json:"file_url"`
+	// Version is the latest version of the module.
+	Version string `json:"version,omitempty"`
+	// Superseded is the path of the latest major version of the module, if it is not this one.
+	Superseded string `json:"superseded,omitempty"`
+	// Readme is the sanitized HTML of the README file of the module, rendered on its landing page.
+	Readme string `json:"readme,omitempty"`
 }
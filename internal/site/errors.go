@@ -0,0 +1,62 @@
+package site
+
+import (
+	"fmt"
+
+	xerrors "go.nhat.io/vanityrender/internal/errors"
+)
+
+// ErrStaleData indicates that some repositories could not be hydrated and their previously published data is used
+// instead.
+const ErrStaleData = xerrors.Error("some repositories use stale data")
+
+var _ error = (*RepositoryError)(nil)
+
+// RepositoryError is an error that occurs while hydrating a repository.
+type RepositoryError struct {
+	Name string
+	Path string
+	Err  error
+}
+
+// Error returns the error message.
+func (e *RepositoryError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *RepositoryError) Unwrap() error {
+	return e.Err
+}
+
+// NewRepositoryError returns a new error for the repository.
+func NewRepositoryError(r Repository, err error) *RepositoryError {
+	return &RepositoryError{
+		Name: r.Name,
+		Path: r.Path,
+		Err:  err,
+	}
+}
+
+// RepositoryErrors returns all the repository errors in the error tree, in order.
+func RepositoryErrors(err error) []*RepositoryError {
+	var result []*RepositoryError
+
+	switch e := err.(type) { // nolint: errorlint
+	case nil:
+		return nil
+
+	case *RepositoryError:
+		return []*RepositoryError{e}
+
+	case interface{ Unwrap() []error }:
+		for _, err := range e.Unwrap() {
+			result = append(result, RepositoryErrors(err)...)
+		}
+
+	case interface{ Unwrap() error }:
+		result = RepositoryErrors(e.Unwrap())
+	}
+
+	return result
+}
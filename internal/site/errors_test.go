@@ -0,0 +1,68 @@
+package site_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/vanityrender/internal/site"
+)
+
+func TestRepositoryError(t *testing.T) {
+	t.Parallel()
+
+	cause := errors.New("could not clone repository: repository not found")
+	err := site.NewRepositoryError(site.Repository{Name: "Repository", Path: "repository"}, cause)
+
+	assert.EqualError(t, err, "repository: could not clone repository: repository not found")
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, "Repository", err.Name)
+	assert.Equal(t, "repository", err.Path)
+}
+
+func TestRepositoryErrors(t *testing.T) {
+	t.Parallel()
+
+	err1 := site.NewRepositoryError(site.Repository{Path: "first"}, errors.New("error 1"))
+	err2 := site.NewRepositoryError(site.Repository{Path: "second"}, errors.New("error 2"))
+	err3 := site.NewRepositoryError(site.Repository{Path: "third"}, errors.New("error 3"))
+
+	testCases := []struct {
+		scenario string
+		err      error
+		expected []*site.RepositoryError
+	}{
+		{
+			scenario: "nil",
+		},
+		{
+			scenario: "not a repository error",
+			err:      errors.New("error"),
+		},
+		{
+			scenario: "repository error",
+			err:      err1,
+			expected: []*site.RepositoryError{err1},
+		},
+		{
+			scenario: "wrapped",
+			err:      fmt.Errorf("wrapped: %w", err1),
+			expected: []*site.RepositoryError{err1},
+		},
+		{
+			scenario: "joined",
+			err:      errors.Join(err1, errors.New("error"), fmt.Errorf("%w: %w", site.ErrStaleData, errors.Join(err2, err3))),
+			expected: []*site.RepositoryError{err1, err2, err3},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, site.RepositoryErrors(tc.err))
+		})
+	}
+}
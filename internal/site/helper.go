@@ -0,0 +1,270 @@
+package site
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aymerick/raymond"
+	"golang.org/x/mod/semver"
+)
+
+const (
+	pkgGoDevURL = "https://pkg.go.dev"
+
+	// dataHost is the private data of the templates that holds the host, i.e. @host.
+	dataHost = "host"
+)
+
+// helpers returns the helpers that are registered in all the templates.
+func helpers() map[string]any {
+	return map[string]any{
+		"pkgGoDevURL":   pkgGoDevURLHelper,
+		"semverCompare": semverCompareHelper,
+		"formatDate":    formatDateHelper,
+		"pluralize":     pluralizeHelper,
+		"join":          joinHelper,
+		"sortBy":        sortByHelper,
+		"groupBy":       groupByHelper,
+	}
+}
+
+// pkgGoDevURLHelper returns the pkg.go.dev URL of a module path relative to the host, at the version given by the
+// version hash argument if any, e.g. {{pkgGoDevURL path version="v1.2.0"}}. It is always a pkg.go.dev URL, the docs URL
+// templates of the site and the repositories are not used, see the docsURL variable of the templates for these.
+func pkgGoDevURLHelper(modulePath string, options *raymond.Options) string {
+	u := fmt.Sprintf("%s/%s", pkgGoDevURL, path.Join(options.DataStr(dataHost), modulePath))
+
+	if v := options.HashStr("version"); len(v) > 0 {
+		u += "@" + v
+	}
+
+	return u
+}
+
+// semverCompareHelper compares two semantic versions with an operator: <, <=, ==, !=, >= or >, e.g.
+// {{#if (semverCompare version ">=" "v2.0.0")}}. An invalid version is less than all the valid ones.
+func semverCompareHelper(v, op, w string) bool {
+	c := semver.Compare(v, w)
+
+	switch op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	case ">=":
+		return c >= 0
+	case ">":
+		return c > 0
+	}
+
+	panic(fmt.Errorf("semverCompare: unknown operator %q", op)) //nolint: err113
+}
+
+// formatDateHelper formats a time.Time or an RFC 3339 string with a Go layout, e.g. {{formatDate date "2006-01-02"}}.
+// The zero and the empty dates are formatted as empty strings.
+func formatDateHelper(date any, layout string) string {
+	var t time.Time
+
+	switch d := date.(type) {
+	case time.Time:
+		t = d
+
+	case *time.Time:
+		if d != nil {
+			t = *d
+		}
+
+	case string:
+		if len(d) == 0 {
+			return ""
+		}
+
+		var err error
+
+		if t, err = time.Parse(time.RFC3339, d); err != nil {
+			panic(fmt.Errorf("formatDate: %w", err))
+		}
+
+	case nil:
+
+	default:
+		panic(fmt.Errorf("formatDate: unsupported date %T", date)) //nolint: err113
+	}
+
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(layout)
+}
+
+// pluralizeHelper prints a count with the singular or the plural form of a word. The count is a number or the length of
+// a list, and the plural form is the singular form with an "s" unless the plural hash argument is set, e.g.
+// {{pluralize modules "module"}} or {{pluralize count "entry" plural="entries"}}.
+func pluralizeHelper(count any, singular string, options *raymond.Options) string {
+	n := toCount(count)
+
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+
+	plural := options.HashStr("plural")
+	if len(plural) == 0 {
+		plural = singular + "s"
+	}
+
+	return fmt.Sprintf("%d %s", n, plural)
+}
+
+// joinHelper joins the items of a list with a separator. With the key hash argument, the field of the items is joined
+// instead, e.g. {{join tags ", "}} or {{join repositories ", " key="name"}}.
+func joinHelper(items any, sep string, options *raymond.Options) string {
+	key := options.HashStr("key")
+	values := toSlice(items)
+	result := make([]string, 0, len(values))
+
+	for _, v := range values {
+		if len(key) > 0 {
+			v = field(v, key)
+		}
+
+		result = append(result, raymond.Str(v))
+	}
+
+	return strings.Join(result, sep)
+}
+
+// sortByHelper returns a copy of the list sorted by a field of the items, in the descending order if the desc hash
+// argument is true, e.g. {{#each (sortBy repositories "latestVersion" desc=true)}}. The semantic versions are compared
+// as versions, the other values as strings.
+func sortByHelper(items any, key string, options *raymond.Options) []any {
+	result := toSlice(items)
+	desc := raymond.IsTrue(options.HashProp("desc"))
+
+	sort.SliceStable(result, func(i, j int) bool {
+		c := compareValues(raymond.Str(field(result[i], key)), raymond.Str(field(result[j], key)))
+
+		if desc {
+			return c > 0
+		}
+
+		return c < 0
+	})
+
+	return result
+}
+
+// groupByHelper groups the items of a list by a field, in the order of the first items of the groups. Each group has
+// the key and the items, e.g. {{#each (groupBy repositories "category")}}{{key}}: {{pluralize items "module"}}{{/each}}.
+func groupByHelper(items any, key string) []map[string]any {
+	var (
+		result  []map[string]any
+		indexes = make(map[string]int)
+	)
+
+	for _, item := range toSlice(items) {
+		k := raymond.Str(field(item, key))
+
+		i, ok := indexes[k]
+		if !ok {
+			i = len(result)
+			indexes[k] = i
+
+			result = append(result, map[string]any{"key": k, "items": []any{}})
+		}
+
+		result[i]["items"] = append(result[i]["items"].([]any), item) //nolint: forcetypeassert
+	}
+
+	return result
+}
+
+// compareValues compares two values as semantic versions if both are valid, as strings otherwise.
+func compareValues(a, b string) int {
+	if semver.IsValid(a) && semver.IsValid(b) {
+		return semver.Compare(a, b)
+	}
+
+	return strings.Compare(a, b)
+}
+
+// toSlice returns the items of a slice or an array, nil otherwise.
+func toSlice(items any) []any {
+	v := reflect.ValueOf(items)
+
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil
+	}
+
+	result := make([]any, v.Len())
+
+	for i := range result {
+		result[i] = v.Index(i).Interface()
+	}
+
+	return result
+}
+
+// toCount returns a number, or the length of a slice, an array or a map.
+func toCount(count any) int {
+	v := reflect.ValueOf(count)
+
+	switch v.Kind() { //nolint: exhaustive
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return v.Len()
+
+	case reflect.String:
+		n, err := strconv.Atoi(v.String())
+		if err != nil {
+			panic(fmt.Errorf("pluralize: invalid count %q", v.String())) //nolint: err113
+		}
+
+		return n
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(v.Int())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(v.Uint()) //nolint: gosec
+
+	case reflect.Float32, reflect.Float64:
+		return int(v.Float())
+
+	case reflect.Invalid:
+		return 0
+	}
+
+	panic(fmt.Errorf("pluralize: unsupported count %T", count)) //nolint: err113
+}
+
+// field returns the field of a map with string keys or of a struct.
+func field(item any, key string) any {
+	v := reflect.Indirect(reflect.ValueOf(item))
+
+	switch v.Kind() { //nolint: exhaustive
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+
+		if f := v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key())); f.IsValid() {
+			return f.Interface()
+		}
+
+	case reflect.Struct:
+		if f := v.FieldByName(key); f.IsValid() && f.CanInterface() {
+			return f.Interface()
+		}
+	}
+
+	return nil
+}
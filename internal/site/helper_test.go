@@ -0,0 +1,127 @@
+package site_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/vanityrender/internal/site"
+)
+
+func TestHelpers(t *testing.T) {
+	t.Parallel()
+
+	s := site.Site{
+		Hostname:        "go.nhat.io",
+		DocsURLTemplate: "https://docs.go.nhat.io/{module}",
+		Repositories: []site.Repository{
+			{Name: "Vanity Render", Path: "vanityrender", LatestVersion: "v0.10.0", RepositoryName: "github.com/nhatthm/govanityrender"},
+			{Name: "Aferomock", Path: "aferomock", LatestVersion: "v0.9.0", RepositoryName: "github.com/nhatthm/aferomock"},
+			{Name: "Clock", Path: "clock", LatestVersion: "v0.2.0", RepositoryName: "github.com/nhatthm/go-clock"},
+			{Name: "Mock", Path: "mock", LatestVersion: "v0.10.0", RepositoryName: "github.com/nhatthm/go-mock"},
+		},
+	}
+
+	testCases := []struct {
+		scenario       string
+		template       string
+		expectedResult string
+		expectedError  string
+	}{
+		{
+			scenario:       "pkgGoDevURL ignores the docs url template",
+			template:       `{{#each repositories}}{{pkgGoDevURL path}} {{/each}}`,
+			expectedResult: "https://pkg.go.dev/go.nhat.io/vanityrender https://pkg.go.dev/go.nhat.io/aferomock https://pkg.go.dev/go.nhat.io/clock https://pkg.go.dev/go.nhat.io/mock ",
+		},
+		{
+			scenario:       "pkgGoDevURL with version",
+			template:       `{{#with repositories.[0]}}{{pkgGoDevURL path version=latestVersion}}{{/with}}`,
+			expectedResult: "https://pkg.go.dev/go.nhat.io/vanityrender@v0.10.0",
+		},
+		{
+			scenario:       "@host",
+			template:       `{{#each repositories}}{{@host}}/{{path}} {{/each}}`,
+			expectedResult: "go.nhat.io/vanityrender go.nhat.io/aferomock go.nhat.io/clock go.nhat.io/mock ",
+		},
+		{
+			scenario:       "semverCompare",
+			template:       `{{#each repositories}}{{#if (semverCompare latestVersion ">=" "v0.9.0")}}{{name}} {{/if}}{{/each}}`,
+			expectedResult: "Vanity Render Aferomock Mock ",
+		},
+		{
+			scenario:       "semverCompare all operators",
+			template:       `{{semverCompare "v1.10.0" "<" "v1.9.0"}} {{semverCompare "v1.0.0" "<=" "v1.0.0"}} {{semverCompare "v1.0.0" "==" "v1.0"}} {{semverCompare "v1.0.0" "!=" "v1.0.1"}} {{semverCompare "v2.0.0" ">" "v1.99.0"}}`,
+			expectedResult: "false true true true true",
+		},
+		{
+			scenario:      "semverCompare unknown operator",
+			template:      `{{semverCompare "v1.0.0" "~" "v1.0.0"}}`,
+			expectedError: `semverCompare: unknown operator "~"`,
+		},
+		{
+			scenario:       "formatDate",
+			template:       `{{formatDate "2024-01-02T03:04:05Z" "Jan 2, 2006"}}|{{formatDate "" "Jan 2, 2006"}}|{{formatDate unknown "Jan 2, 2006"}}`,
+			expectedResult: "Jan 2, 2024||",
+		},
+		{
+			scenario:      "formatDate invalid date",
+			template:      `{{formatDate "yesterday" "Jan 2, 2006"}}`,
+			expectedError: `formatDate: parsing time "yesterday"`,
+		},
+		{
+			scenario:       "pluralize",
+			template:       `{{pluralize repositories "repository" plural="repositories"}}, {{pluralize 1 "module"}}, {{pluralize 0 "module"}}`,
+			expectedResult: "4 repositories, 1 module, 0 modules",
+		},
+		{
+			scenario:       "join",
+			template:       `{{join repositories ", " key="name"}}`,
+			expectedResult: "Vanity Render, Aferomock, Clock, Mock",
+		},
+		{
+			scenario:       "sortBy",
+			template:       `{{#each (sortBy repositories "name")}}{{name}} {{/each}}`,
+			expectedResult: "Aferomock Clock Mock Vanity Render ",
+		},
+		{
+			scenario:       "sortBy version desc",
+			template:       `{{#each (sortBy repositories "latestVersion" desc=true)}}{{path}}@{{latestVersion}} {{/each}}`,
+			expectedResult: "vanityrender@v0.10.0 mock@v0.10.0 aferomock@v0.9.0 clock@v0.2.0 ",
+		},
+		{
+			scenario:       "groupBy",
+			template:       `{{#each (groupBy repositories "latestVersion")}}{{key}}: {{join items "," key="path"}} ({{pluralize items "module"}}); {{/each}}`,
+			expectedResult: "v0.10.0: vanityrender,mock (2 modules); v0.9.0: aferomock (1 module); v0.2.0: clock (1 module); ",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			outputDir := t.TempDir()
+
+			r, err := site.NewHandlebarsRenderder(
+				site.NewTemplate("homepage.html.hbs", tc.template),
+				site.NewTemplate("404.html.hbs", ""),
+				site.NewTemplate("repository.html.hbs", ""),
+				outputDir,
+			)
+			require.NoError(t, err)
+
+			err = r.Render(t.Context(), s)
+
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expectedResult, fileContent(t, filepath.Join(outputDir, "index.html")))
+		})
+	}
+}
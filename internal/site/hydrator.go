@@ -1,14 +1,30 @@
 package site
 
+import "context"
+
 // Hydrator hydrates configuration.
 type Hydrator interface {
-	Hydrate(s *Site) error
+	Hydrate(ctx context.Context, s *Site) error
 }
 
+var _ Hydrator = (Hydrators)(nil)
+
+// Hydrators is a Hydrator that runs the hydrators in order.
+type Hydrators []Hydrator
+
 // Hydrate hydrates the configuration.
-func Hydrate(s *Site, hydrators ...Hydrator) error {
+func (h Hydrators) Hydrate(ctx context.Context, s *Site) error {
+	return Hydrate(ctx, s, h...)
+}
+
+// Hydrate hydrates the configuration.
+func Hydrate(ctx context.Context, s *Site, hydrators ...Hydrator) error {
 	for _, hydrator := range hydrators {
-		if err := hydrator.Hydrate(s); err != nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := hydrator.Hydrate(ctx, s); err != nil {
 			return err
 		}
 	}
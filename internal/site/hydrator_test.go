@@ -1,6 +1,7 @@
 package site_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -36,15 +37,60 @@ func TestHydrate(t *testing.T) {
 		t.Run(tc.scenario, func(t *testing.T) {
 			t.Parallel()
 
-			actual := site.Hydrate(&site.Site{}, tc.hydrator)
+			actual := site.Hydrate(t.Context(), &site.Site{}, tc.hydrator)
 
 			assert.Equal(t, tc.expected, actual)
 		})
 	}
 }
 
+func TestHydrators_Hydrate(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+
+	h := site.Hydrators{
+		hydrateFunc(func(s *site.Site) error {
+			calls = append(calls, "first")
+			s.PageTitle = "title"
+
+			return nil
+		}),
+		hydrateFunc(func(s *site.Site) error {
+			calls = append(calls, "second:"+s.PageTitle)
+
+			return errors.New("error")
+		}),
+		hydrateFunc(func(*site.Site) error {
+			t.Error("unexpected call")
+
+			return nil
+		}),
+	}
+
+	err := h.Hydrate(t.Context(), &site.Site{})
+
+	assert.EqualError(t, err, "error")
+	assert.Equal(t, []string{"first", "second:title"}, calls)
+}
+
+func TestHydrate_Canceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	actual := site.Hydrate(ctx, &site.Site{}, hydrateFunc(func(*site.Site) error {
+		t.Error("unexpected call")
+
+		return nil
+	}))
+
+	assert.ErrorIs(t, actual, context.Canceled)
+}
+
 type hydrateFunc func(s *site.Site) error
 
-func (f hydrateFunc) Hydrate(s *site.Site) error {
+func (f hydrateFunc) Hydrate(_ context.Context, s *site.Site) error {
 	return f(s)
 }
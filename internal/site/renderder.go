@@ -1,25 +1,46 @@
 package site
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/aymerick/raymond"
 	"github.com/fatih/color"
+	"golang.org/x/mod/semver"
 
+	"go.nhat.io/vanityrender/internal/module"
 	"go.nhat.io/vanityrender/internal/version"
 )
 
 const (
 	indexHTMLFile    = `index.html`
 	notFoundHTMLFile = `404.html`
+	assetsDir        = `assets`
+
+	// homepageScript is the asset of the scripts of the homepage, inlined in the page unless the inline scripts are
+	// disabled.
+	homepageScript = `vanityrender.js`
+
+	// uncategorized is the category of the repositories without a category, when the others have one.
+	uncategorized = `Other`
+
+	// defaultDocsURLTemplate is the documentation of the modules when the site does not configure one.
+	defaultDocsURLTemplate = pkgGoDevURL + `/{module}`
 )
 
+var anchorRegExp = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+
 // Renderder is the interface for rendering.
 type Renderder interface {
-	Render(s Site) error
+	Render(ctx context.Context, s Site) error
 }
 
 var _ Renderder = (*HandlebarsRenderder)(nil)
@@ -29,13 +50,23 @@ type HandlebarsRenderder struct {
 	homepageTpl   *raymond.Template
 	notFoundTpl   *raymond.Template
 	repositoryTpl *raymond.Template
-	outputDir     string
+	// moduleTpl renders the landing pages of the modules instead of the repository template, if set.
+	moduleTpl *raymond.Template
+	partials  map[string]Template
+	assets    map[string][]byte
+	outputDir string
+
+	moduleTemplate *Template
 
 	output io.Writer
 }
 
 // Render renders the configuration.
-func (h *HandlebarsRenderder) Render(s Site) error {
+func (h *HandlebarsRenderder) Render(ctx context.Context, s Site) error {
+	if err := h.renderAssets(s); err != nil {
+		return fmt.Errorf("could not render assets: %w", err)
+	}
+
 	if err := h.renderHomepage(s); err != nil {
 		return fmt.Errorf("could not render homepage: %w", err)
 	}
@@ -45,9 +76,43 @@ func (h *HandlebarsRenderder) Render(s Site) error {
 	}
 
 	for _, r := range s.Repositories {
-		if err := h.renderRepository(s.Hostname, r); err != nil {
+		if err := h.renderRepository(ctx, s, r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renderAssets writes the assets into the assets directory when the pages are self-contained, or only the script of the
+// homepage when the pages load their scripts from a file but the rest from the CDNs.
+func (h *HandlebarsRenderder) renderAssets(s Site) error {
+	if !s.SelfContained && !s.NoInlineScripts {
+		return nil
+	}
+
+	names := make([]string, 0, len(h.assets))
+
+	for name := range h.assets {
+		if s.SelfContained || name == homepageScript {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		assetFile := filepath.Join(h.outputDir, assetsDir, filepath.FromSlash(name))
+
+		if err := os.MkdirAll(filepath.Dir(assetFile), 0o755); err != nil { // nolint: gosec
+			return err
+		}
+
+		if err := os.WriteFile(assetFile, h.assets[name], 0o644); err != nil { // nolint: gosec
 			return err
 		}
+
+		_, _ = fmt.Fprintln(h.output, color.HiGreenString("Render"), ":", path.Join(assetsDir, name)) //nolint: errcheck
 	}
 
 	return nil
@@ -58,15 +123,7 @@ func (h *HandlebarsRenderder) renderHomepage(s Site) error {
 
 	repositories := make([]map[string]any, len(s.Repositories))
 	for i, r := range s.Repositories {
-		repositories[i] = map[string]any{
-			"name":           r.Name,
-			"path":           r.Path,
-			"deprecated":     r.Deprecated,
-			"hidden":         r.Hidden,
-			"repositoryURL":  r.RepositoryURL,
-			"repositoryName": r.RepositoryName,
-			"latestVersion":  r.LatestVersion,
-		}
+		repositories[i] = homepageRepository(s, r)
 	}
 
 	inputs := map[string]any{
@@ -75,10 +132,17 @@ func (h *HandlebarsRenderder) renderHomepage(s Site) error {
 		"host":            s.Hostname,
 		"sourceURL":       s.SourceURL,
 		"repositories":    repositories,
+		"categorized":     isCategorized(s.Repositories),
+		"categories":      homepageCategories(s),
+		"search":          s.Search,
+		"selfContained":   s.SelfContained,
+		"inlineScripts":   !s.NoInlineScripts,
+		"script":          string(h.assets[homepageScript]),
 		"renderer":        version.Info(),
+		"params":          s.Params,
 	}
 
-	result, err := h.homepageTpl.Exec(inputs)
+	result, err := h.homepageTpl.ExecWith(inputs, privateData(s.Hostname))
 	if err != nil {
 		return err
 	}
@@ -100,10 +164,13 @@ func (h *HandlebarsRenderder) render404(s Site) error {
 		"pageDescription": s.PageDescription,
 		"host":            s.Hostname,
 		"sourceURL":       s.SourceURL,
+		"selfContained":   s.SelfContained,
+		"inlineScripts":   !s.NoInlineScripts,
 		"renderer":        version.Info(),
+		"params":          s.Params,
 	}
 
-	result, err := h.notFoundTpl.Exec(inputs)
+	result, err := h.notFoundTpl.ExecWith(inputs, privateData(s.Hostname))
 	if err != nil {
 		return err
 	}
@@ -117,9 +184,13 @@ func (h *HandlebarsRenderder) render404(s Site) error {
 	return nil
 }
 
-func (h *HandlebarsRenderder) renderRepository(host string, r Repository) error {
+func (h *HandlebarsRenderder) renderRepository(ctx context.Context, s Site, r Repository) error {
 	for _, m := range r.Modules {
-		if err := h.renderModule(host, m); err != nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := h.renderModule(s, r, m); err != nil {
 			return err
 		}
 	}
@@ -127,7 +198,7 @@ func (h *HandlebarsRenderder) renderRepository(host string, r Repository) error
 	return nil
 }
 
-func (h *HandlebarsRenderder) renderModule(host string, m Module) error {
+func (h *HandlebarsRenderder) renderModule(s Site, r Repository, m Module) error {
 	moduleDir := filepath.Join(h.outputDir, m.Path)
 
 	if err := os.MkdirAll(moduleDir, 0o755); err != nil { // nolint: gosec
@@ -135,19 +206,47 @@ func (h *HandlebarsRenderder) renderModule(host string, m Module) error {
 	}
 
 	moduleFile := filepath.Join(moduleDir, indexHTMLFile)
+	docs := moduleDocsURL(s, r, m.Path, m.Version)
 
 	ctx := map[string]any{
-		"host":          host,
-		"path":          m.Path,
-		"importPrefix":  m.ImportPrefix,
-		"vcs":           m.VCS,
-		"repositoryURL": m.RepositoryURL,
-		"homeURL":       m.HomeURL,
-		"directoryURL":  m.DirectoryURL,
-		"fileURL":       m.FileURL,
+		"pageTitle":         fmt.Sprintf("%s/%s", s.Hostname, m.Path),
+		"pageDescription":   firstNonEmpty(r.Description, s.PageDescription),
+		"host":              s.Hostname,
+		"sourceURL":         s.SourceURL,
+		"path":              m.Path,
+		"importPrefix":      m.ImportPrefix,
+		"vcs":               m.VCS,
+		"repositoryURL":     m.RepositoryURL,
+		"homeURL":           m.HomeURL,
+		"directoryURL":      m.DirectoryURL,
+		"fileURL":           m.FileURL,
+		"version":           m.Version,
+		"superseded":        m.Superseded,
+		"readme":            m.Readme,
+		"redirect":          !s.DisableRedirect,
+		"docsURL":           docs,
+		"docsHost":          docsHost(docs),
+		"supersededDocsURL": supersededDocsURL(s, r, m),
+		"name":              r.Name,
+		"description":       r.Description,
+		"deprecated":        r.Deprecated,
+		"latestVersion":     r.LatestVersion,
+		"license":           r.License,
+		"homepage":          r.Homepage,
+		"majors":            majorVersions(r, m),
+		"submodules":        submodules(r, m),
+		"selfContained":     s.SelfContained,
+		"inlineScripts":     !s.NoInlineScripts,
+		"renderer":          version.Info(),
+		"params":            mergeParams(s.Params, r.Params),
 	}
 
-	result, err := h.repositoryTpl.Exec(ctx)
+	tpl := h.repositoryTpl
+	if h.moduleTpl != nil {
+		tpl = h.moduleTpl
+	}
+
+	result, err := tpl.ExecWith(ctx, privateData(s.Hostname))
 	if err != nil {
 		return fmt.Errorf("could not render repository %q: %w", m.ImportPrefix, err)
 	}
@@ -161,37 +260,365 @@ func (h *HandlebarsRenderder) renderModule(host string, m Module) error {
 	return nil
 }
 
+// privateData returns the private data of the templates, e.g. @host.
+func privateData(host string) *raymond.DataFrame {
+	data := raymond.NewDataFrame()
+	data.Set(dataHost, host)
+
+	return data
+}
+
+// homepageRepository returns the inputs of a repository on the homepage.
+func homepageRepository(s Site, r Repository) map[string]any {
+	latestPath := firstNonEmpty(r.LatestPath, r.Path)
+
+	return map[string]any{
+		"name":           r.Name,
+		"path":           latestPath,
+		"deprecated":     r.Deprecated,
+		"hidden":         r.Hidden,
+		"repositoryURL":  r.RepositoryURL,
+		"repositoryName": r.RepositoryName,
+		"latestVersion":  r.LatestVersion,
+		"superseded":     supersededVersions(s, r),
+		"description":    r.Description,
+		"tags":           r.Tags,
+		"homepage":       r.Homepage,
+		"docsURL":        moduleDocsURL(s, r, latestPath, r.LatestVersion),
+		"license":        r.License,
+		"category":       r.Category,
+		"params":         mergeParams(s.Params, r.Params),
+	}
+}
+
+// isCategorized returns true if a visible repository has a category.
+func isCategorized(repos []Repository) bool {
+	for _, r := range repos {
+		if !r.Hidden && len(r.Category) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// homepageCategories groups the visible repositories by category. The categories of the site go first in their order,
+// then the other ones in the order of their first repositories, then the repositories without a category. Each category
+// has a name, a unique anchor and the repositories.
+func homepageCategories(s Site) []map[string]any {
+	if !isCategorized(s.Repositories) {
+		return uncategorizedRepositories(s)
+	}
+
+	var names []string
+
+	groups := make(map[string][]map[string]any)
+
+	for _, name := range s.Categories {
+		if _, ok := groups[name]; !ok && len(name) > 0 {
+			names = append(names, name)
+			groups[name] = nil
+		}
+	}
+
+	var others []map[string]any
+
+	for _, r := range s.Repositories {
+		if r.Hidden {
+			continue
+		}
+
+		if len(r.Category) == 0 {
+			others = append(others, homepageRepository(s, r))
+
+			continue
+		}
+
+		if _, ok := groups[r.Category]; !ok {
+			names = append(names, r.Category)
+		}
+
+		groups[r.Category] = append(groups[r.Category], homepageRepository(s, r))
+	}
+
+	// The repositories without a category join the category of the same name, if any.
+	if _, ok := groups[uncategorized]; ok {
+		groups[uncategorized] = append(groups[uncategorized], others...)
+		others = nil
+	}
+
+	result := make([]map[string]any, 0, len(names)+1)
+	anchors := make(map[string]int, len(names)+1)
+
+	add := func(name string, repos []map[string]any) {
+		if len(repos) == 0 {
+			return
+		}
+
+		result = append(result, map[string]any{
+			"name":         name,
+			"anchor":       uniqueAnchor(anchors, name),
+			"repositories": repos,
+		})
+	}
+
+	for _, name := range names {
+		add(name, groups[name])
+	}
+
+	add(uncategorized, others)
+
+	return result
+}
+
+// uncategorizedRepositories returns the visible repositories in a category without a name, when none has a category.
+func uncategorizedRepositories(s Site) []map[string]any {
+	repos := make([]map[string]any, 0, len(s.Repositories))
+
+	for _, r := range s.Repositories {
+		if !r.Hidden {
+			repos = append(repos, homepageRepository(s, r))
+		}
+	}
+
+	if len(repos) == 0 {
+		return nil
+	}
+
+	return []map[string]any{{"name": "", "anchor": "", "repositories": repos}}
+}
+
+// uniqueAnchor returns the anchor of a name, e.g. "Testing Tools" becomes "testing-tools", with a suffix if the anchor is
+// already used, e.g. "testing-tools-2".
+func uniqueAnchor(anchors map[string]int, name string) string {
+	anchor := strings.Trim(anchorRegExp.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if len(anchor) == 0 {
+		anchor = "category"
+	}
+
+	anchors[anchor]++
+
+	if n := anchors[anchor]; n > 1 {
+		return fmt.Sprintf("%s-%d", anchor, n)
+	}
+
+	return anchor
+}
+
+// mergeParams returns the params of the site with the ones of the repository over them. The nested objects are merged
+// too, so a repository can override a single field of an object of the site.
+func mergeParams(site, repo map[string]any) map[string]any {
+	if len(repo) == 0 {
+		return site
+	}
+
+	if len(site) == 0 {
+		return repo
+	}
+
+	result := make(map[string]any, len(site)+len(repo))
+
+	for k, v := range site {
+		result[k] = v
+	}
+
+	for k, v := range repo {
+		sv, sOk := result[k].(map[string]any)
+		rv, rOk := v.(map[string]any)
+
+		if sOk && rOk {
+			v = mergeParams(sv, rv)
+		}
+
+		result[k] = v
+	}
+
+	return result
+}
+
+// supersededVersions returns the older major versions of the repository, newest first. The untagged modules are ignored.
+func supersededVersions(s Site, r Repository) []map[string]any {
+	latestPath := firstNonEmpty(r.LatestPath, r.Path)
+	modules := make([]Module, 0, len(r.Modules))
+
+	for _, m := range r.Modules {
+		if m.Superseded == latestPath && len(m.Version) > 0 && m.Version != "v0.0.0" {
+			modules = append(modules, m)
+		}
+	}
+
+	sort.Slice(modules, func(i, j int) bool {
+		return semver.Compare(modules[i].Version, modules[j].Version) > 0
+	})
+
+	result := make([]map[string]any, len(modules))
+
+	for i, m := range modules {
+		result[i] = map[string]any{
+			"path":    m.Path,
+			"version": m.Version,
+			"docsURL": docsURL(s, r, m.Path, m.Version),
+		}
+	}
+
+	return result
+}
+
+// moduleDocsURL returns the documentation URL of a module of the repository. The docs URL of the repository is the one
+// of its latest module, so the homepage, the module page and its redirect link to the same page.
+func moduleDocsURL(s Site, r Repository, modulePath, version string) string {
+	if modulePath == firstNonEmpty(r.LatestPath, r.Path) {
+		return firstNonEmpty(r.DocsURL, docsURL(s, r, modulePath, version))
+	}
+
+	return docsURL(s, r, modulePath, version)
+}
+
+// docsURL returns the documentation URL of a module of the repository, from the template of the repository or the one
+// of the site, e.g. https://pkg.go.dev/go.nhat.io/mock/v2. The version suffix is left out for the untagged modules.
+func docsURL(s Site, r Repository, modulePath, version string) string {
+	tpl := firstNonEmpty(r.DocsURLTemplate, s.DocsURLTemplate, defaultDocsURLTemplate)
+
+	if len(version) == 0 {
+		tpl = strings.ReplaceAll(tpl, "@{version}", "")
+	}
+
+	return strings.NewReplacer(
+		"{module}", path.Join(s.Hostname, modulePath),
+		"{path}", modulePath,
+		"{version}", version,
+	).Replace(tpl)
+}
+
+// supersededDocsURL returns the documentation URL of the latest major version of the module, if it is superseded.
+func supersededDocsURL(s Site, r Repository, m Module) string {
+	if len(m.Superseded) == 0 {
+		return ""
+	}
+
+	version := ""
+
+	for _, o := range r.Modules {
+		if o.Path == m.Superseded {
+			version = o.Version
+		}
+	}
+
+	return moduleDocsURL(s, r, m.Superseded, version)
+}
+
+// docsHost returns the host of the documentation URL, e.g. pkg.go.dev.
+func docsHost(docsURL string) string {
+	u, err := url.Parse(docsURL)
+	if err != nil {
+		return docsURL
+	}
+
+	return u.Host
+}
+
+// majorVersions returns the major versions of the module, newest first, or nil if it has only one.
+func majorVersions(r Repository, m Module) []map[string]any {
+	base := module.PathWithoutVersion(m.Path)
+	modules := make([]Module, 0, len(r.Modules))
+
+	for _, o := range r.Modules {
+		if module.PathWithoutVersion(o.Path) == base {
+			modules = append(modules, o)
+		}
+	}
+
+	if len(modules) < 2 {
+		return nil
+	}
+
+	sort.SliceStable(modules, func(i, j int) bool {
+		return semver.Compare(modules[i].Version, modules[j].Version) > 0
+	})
+
+	result := make([]map[string]any, len(modules))
+
+	for i, o := range modules {
+		result[i] = map[string]any{
+			"path":    o.Path,
+			"version": o.Version,
+			"current": o.Path == m.Path,
+		}
+	}
+
+	return result
+}
+
+// submodules returns the latest major versions of the modules nested in the directory of the module, e.g. mock/sub of
+// mock, but neither mock/v2 nor mock/v2/sub.
+func submodules(r Repository, m Module) []map[string]any {
+	var result []map[string]any
+
+	for _, o := range r.Modules {
+		rel, ok := strings.CutPrefix(o.Path, m.Path+"/")
+		if !ok || len(o.Superseded) > 0 {
+			continue
+		}
+
+		if dir, _, _ := strings.Cut(rel, "/"); module.MajorVersionRegExp.MatchString(dir) {
+			continue
+		}
+
+		result = append(result, map[string]any{
+			"path":    o.Path,
+			"version": o.Version,
+		})
+	}
+
+	return result
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if len(v) > 0 {
+			return v
+		}
+	}
+
+	return ""
+}
+
 // NewHandlebarsRenderder creates a new HandlebarsRenderder.
 func NewHandlebarsRenderder(
-	homepageSrc, notFoundSrc, repositorySrc string,
+	homepage, notFound, repository Template,
 	outputDir string,
 	opts ...RendererOption,
 ) (*HandlebarsRenderder, error) {
-	homepageTpl, err := raymond.Parse(homepageSrc)
+	r := &HandlebarsRenderder{
+		outputDir: outputDir,
+		output:    io.Discard,
+	}
+
+	for _, o := range opts {
+		o.applyRendererOption(r)
+	}
+
+	partials, err := parsePartials(r.partials)
 	if err != nil {
+		return nil, fmt.Errorf("could not parse partial: %w", err)
+	}
+
+	if r.homepageTpl, err = parseTemplate(homepage, partials); err != nil {
 		return nil, fmt.Errorf("could not parse homepage template: %w", err)
 	}
 
-	notFoundTpl, err := raymond.Parse(notFoundSrc)
-	if err != nil {
+	if r.notFoundTpl, err = parseTemplate(notFound, partials); err != nil {
 		return nil, fmt.Errorf("could not parse 404 template: %w", err)
 	}
 
-	repositoryTpl, err := raymond.Parse(repositorySrc)
-	if err != nil {
+	if r.repositoryTpl, err = parseTemplate(repository, partials); err != nil {
 		return nil, fmt.Errorf("could not parse repository template: %w", err)
 	}
 
-	r := &HandlebarsRenderder{
-		homepageTpl:   homepageTpl,
-		notFoundTpl:   notFoundTpl,
-		repositoryTpl: repositoryTpl,
-		outputDir:     outputDir,
-		output:        io.Discard,
-	}
-
-	for _, o := range opts {
-		o.applyRendererOption(r)
+	if r.moduleTemplate != nil {
+		if r.moduleTpl, err = parseTemplate(*r.moduleTemplate, partials); err != nil {
+			return nil, fmt.Errorf("could not parse module template: %w", err)
+		}
 	}
 
 	return r, nil
@@ -214,3 +641,39 @@ func WithOutput(w io.Writer) RendererOption {
 		r.output = w
 	})
 }
+
+// WithModuleTemplate renders a landing page for each module with the template, instead of the repository template that
+// redirects to pkg.go.dev.
+func WithModuleTemplate(t Template) RendererOption {
+	return rendererOptionFunc(func(r *HandlebarsRenderder) {
+		r.moduleTemplate = &t
+	})
+}
+
+// WithAssets sets the stylesheets, the scripts and the images by their paths relative to the assets directory, e.g.
+// vanityrender.css. The assets set later replace the ones with the same paths.
+func WithAssets(assets map[string][]byte) RendererOption {
+	return rendererOptionFunc(func(r *HandlebarsRenderder) {
+		if r.assets == nil {
+			r.assets = make(map[string][]byte, len(assets))
+		}
+
+		for name, data := range assets {
+			r.assets[name] = data
+		}
+	})
+}
+
+// WithPartials registers the partials by name in all the templates, e.g. footer for {{> footer}}. The partials
+// registered later replace the ones with the same names.
+func WithPartials(partials map[string]Template) RendererOption {
+	return rendererOptionFunc(func(r *HandlebarsRenderder) {
+		if r.partials == nil {
+			r.partials = make(map[string]Template, len(partials))
+		}
+
+		for name, p := range partials {
+			r.partials[name] = p
+		}
+	})
+}
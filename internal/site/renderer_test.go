@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -22,25 +24,34 @@ func TestNewHandlebarsRenderder(t *testing.T) {
 		homepageSrc   string
 		NotFoundSrc   string
 		repositorySrc string
+		moduleSrc     string
 		expectedError string
 	}{
 		{
 			scenario:      "homepage template is broken",
 			homepageSrc:   `{{`,
-			expectedError: "could not parse homepage template: Parse error on line 1:",
+			expectedError: "could not parse homepage template: homepage.html.hbs:1: ",
 		},
 		{
 			scenario:      "not found template is broken",
 			homepageSrc:   `{{ message }}`,
-			NotFoundSrc:   `{{`,
-			expectedError: "could not parse 404 template: Parse error on line 1:",
+			NotFoundSrc:   "<html>\n  <body>\n    {{ message }\n  </body>\n</html>\n",
+			expectedError: "could not parse 404 template: 404.html.hbs:3: ",
 		},
 		{
 			scenario:      "repository template is broken",
 			homepageSrc:   `{{ message }}`,
 			NotFoundSrc:   `{{ message }}`,
-			repositorySrc: `{{`,
-			expectedError: "could not parse repository template: Parse error on line 1:",
+			repositorySrc: "\n{{ message }",
+			expectedError: "could not parse repository template: repository.html.hbs:2: ",
+		},
+		{
+			scenario:      "module template is broken",
+			homepageSrc:   `{{ message }}`,
+			NotFoundSrc:   `{{ message }}`,
+			repositorySrc: `{{ message }}`,
+			moduleSrc:     "{{#if message}}",
+			expectedError: "could not parse module template: module.html.hbs:1: ",
 		},
 		{
 			scenario:      "success",
@@ -48,25 +59,679 @@ func TestNewHandlebarsRenderder(t *testing.T) {
 			NotFoundSrc:   `{{ message }}`,
 			repositorySrc: `{{ message }}`,
 		},
+		{
+			scenario:      "success with module template",
+			homepageSrc:   `{{ message }}`,
+			NotFoundSrc:   `{{ message }}`,
+			repositorySrc: `{{ message }}`,
+			moduleSrc:     `{{ message }}`,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.scenario, func(t *testing.T) {
 			t.Parallel()
 
-			actual, err := site.NewHandlebarsRenderder(tc.homepageSrc, tc.NotFoundSrc, tc.repositorySrc, "")
+			var opts []site.RendererOption
+
+			if tc.moduleSrc != "" {
+				opts = append(opts, site.WithModuleTemplate(site.NewTemplate("module.html.hbs", tc.moduleSrc)))
+			}
+
+			actual, err := site.NewHandlebarsRenderder(
+				site.NewTemplate("homepage.html.hbs", tc.homepageSrc),
+				site.NewTemplate("404.html.hbs", tc.NotFoundSrc),
+				site.NewTemplate("repository.html.hbs", tc.repositorySrc),
+				"",
+				opts...,
+			)
 
 			if tc.expectedError == "" {
 				assert.NotNil(t, actual)
 				assert.NoError(t, err)
 			} else {
+				var tErr *site.TemplateError
+
 				assert.Nil(t, actual)
 				assert.ErrorContains(t, err, tc.expectedError)
+				assert.ErrorAs(t, err, &tErr)
+			}
+		})
+	}
+}
+
+func TestNewHandlebarsRenderder_Partials(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario       string
+		partials       []map[string]site.Template
+		expectedResult string
+		expectedError  string
+	}{
+		{
+			scenario: "partial is broken",
+			partials: []map[string]site.Template{
+				{"footer": site.NewTemplate("partials/footer.hbs", "<p>\n{{ message }\n</p>")},
+			},
+			expectedError: "could not parse partial: partials/footer.hbs:2: ",
+		},
+		{
+			scenario: "partial is missing",
+			partials: []map[string]site.Template{
+				{"header": site.NewTemplate("partials/header.hbs", "<h1>{{ pageTitle }}</h1>\n")},
+			},
+			expectedError: "could not render homepage: Evaluation error: Partial not found: footer",
+		},
+		{
+			scenario: "partials",
+			partials: []map[string]site.Template{
+				{
+					"header": site.NewTemplate("partials/header.hbs", "<h1>{{ pageTitle }}</h1>\n"),
+					"footer": site.NewTemplate("partials/footer.hbs", "<p>{{> copyright}}</p>"),
+				},
+				{
+					"copyright": site.NewTemplate("partials/copyright.hbs", "&copy; {{ host }}"),
+				},
+			},
+			expectedResult: "<h1>Example</h1>\n<main></main>\n<p>&copy; example.com</p>",
+		},
+		{
+			scenario: "override partial",
+			partials: []map[string]site.Template{
+				{
+					"header": site.NewTemplate("partials/header.hbs", "<h1>{{ pageTitle }}</h1>\n"),
+					"footer": site.NewTemplate("partials/footer.hbs", "<p>Built-in</p>"),
+				},
+				{
+					"footer": site.NewTemplate("custom/footer.hbs", "<p>Custom</p>"),
+				},
+			},
+			expectedResult: "<h1>Example</h1>\n<main></main>\n<p>Custom</p>",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			outputDir := t.TempDir()
+			opts := make([]site.RendererOption, 0, len(tc.partials))
+
+			for _, p := range tc.partials {
+				opts = append(opts, site.WithPartials(p))
+			}
+
+			r, err := site.NewHandlebarsRenderder(
+				site.NewTemplate("homepage.html.hbs", "{{> header}}\n<main></main>\n{{> footer}}"),
+				site.NewTemplate("404.html.hbs", "{{> footer}}"),
+				site.NewTemplate("repository.html.hbs", "{{> footer}}"),
+				outputDir,
+				opts...,
+			)
+
+			if err == nil {
+				err = r.Render(t.Context(), site.Site{PageTitle: "Example", Hostname: "example.com"})
+			}
+
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+
+				return
 			}
+
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expectedResult, fileContent(t, filepath.Join(outputDir, "index.html")))
 		})
 	}
 }
 
+func TestHandlebarsRenderder_Render_Params(t *testing.T) {
+	t.Parallel()
+
+	outputDir := t.TempDir()
+
+	r, err := site.NewHandlebarsRenderder(
+		site.NewTemplate("homepage.html.hbs", "{{ params.logoURL }}|{{#each repositories}}{{ path }}={{ params.badge }}/{{ params.analytics.id }} {{/each}}"),
+		site.NewTemplate("404.html.hbs", "{{ params.logoURL }}|{{ params.contact }}"),
+		site.NewTemplate("repository.html.hbs", "{{ params.logoURL }}|{{ params.badge }}|{{ params.analytics.id }}/{{ params.analytics.enabled }}"),
+		outputDir,
+	)
+	require.NoError(t, err)
+
+	s := site.Site{
+		Hostname: "example.com",
+		Params: map[string]any{
+			"logoURL":   "https://example.com/logo.svg",
+			"contact":   "john.doe@example.com",
+			"badge":     "stable",
+			"analytics": map[string]any{"id": "G-123", "enabled": true},
+		},
+		Repositories: []site.Repository{
+			{
+				Path:    "lib",
+				Modules: []site.Module{{Path: "lib"}},
+			},
+			{
+				Path:    "beta",
+				Modules: []site.Module{{Path: "beta"}, {Path: "beta/v2"}},
+				Params: map[string]any{
+					"badge":     "experimental",
+					"analytics": map[string]any{"id": "G-456"},
+				},
+			},
+		},
+	}
+
+	err = r.Render(t.Context(), s)
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://example.com/logo.svg|lib=stable/G-123 beta=experimental/G-456 ", fileContent(t, filepath.Join(outputDir, "index.html")))
+	assert.Equal(t, "https://example.com/logo.svg|john.doe@example.com", fileContent(t, filepath.Join(outputDir, "404.html")))
+	assert.Equal(t, "https://example.com/logo.svg|stable|G-123/true", fileContent(t, filepath.Join(outputDir, "lib", "index.html")))
+	assert.Equal(t, "https://example.com/logo.svg|experimental|G-456/true", fileContent(t, filepath.Join(outputDir, "beta", "v2", "index.html")))
+
+	// The params of the site are not modified by the ones of the repositories.
+	assert.Equal(t, map[string]any{"id": "G-123", "enabled": true}, s.Params["analytics"])
+}
+
+func TestHandlebarsRenderder_Render_Categories(t *testing.T) {
+	t.Parallel()
+
+	repo := func(p, category string) site.Repository {
+		return site.Repository{Name: p, Path: p, Category: category}
+	}
+
+	testCases := []struct {
+		scenario       string
+		site           site.Site
+		expectedResult string
+	}{
+		{
+			scenario:       "no repositories",
+			expectedResult: "false|",
+		},
+		{
+			scenario: "no categories",
+			site: site.Site{
+				Repositories: []site.Repository{repo("a", ""), {Path: "hidden", Hidden: true}, repo("b", "")},
+			},
+			expectedResult: "false|:=a,b;",
+		},
+		{
+			scenario: "only hidden repositories have categories",
+			site: site.Site{
+				Repositories: []site.Repository{repo("a", ""), {Path: "hidden", Category: "Hidden", Hidden: true}},
+			},
+			expectedResult: "false|:=a;",
+		},
+		{
+			scenario: "categories in the order of the repositories",
+			site: site.Site{
+				Repositories: []site.Repository{repo("a", "Testing"), repo("b", ""), repo("c", "Web Tools"), repo("d", "Testing")},
+			},
+			expectedResult: "true|testing:Testing=a,d;web-tools:Web Tools=c;other:Other=b;",
+		},
+		{
+			scenario: "categories in the configured order",
+			site: site.Site{
+				Categories:   []string{"Web Tools", "Empty", "Other", "Testing", "Web Tools"},
+				Repositories: []site.Repository{repo("a", "Testing"), repo("b", ""), repo("c", "Web Tools"), repo("d", "CLI")},
+			},
+			expectedResult: "true|web-tools:Web Tools=c;other:Other=b;testing:Testing=a;cli:CLI=d;",
+		},
+		{
+			scenario: "anchors are unique",
+			site: site.Site{
+				Repositories: []site.Repository{repo("a", "Web Tools"), repo("b", "web-tools"), repo("c", "!!!")},
+			},
+			expectedResult: "true|web-tools:Web Tools=a;web-tools-2:web-tools=b;category:!!!=c;",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			outputDir := t.TempDir()
+
+			r, err := site.NewHandlebarsRenderder(
+				site.NewTemplate("homepage.html.hbs", `{{ categorized }}|{{#each categories}}{{ anchor }}:{{ name }}={{join repositories "," key="path"}};{{/each}}`),
+				site.NewTemplate("404.html.hbs", ""),
+				site.NewTemplate("repository.html.hbs", ""),
+				outputDir,
+			)
+			require.NoError(t, err)
+
+			err = r.Render(t.Context(), tc.site)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expectedResult, fileContent(t, filepath.Join(outputDir, "index.html")))
+		})
+	}
+}
+
+func TestHandlebarsRenderder_Render_EmbeddedCategories(t *testing.T) {
+	t.Parallel()
+
+	outputDir := t.TempDir()
+
+	r, err := site.NewHandlebarsRenderder(
+		site.NewTemplate(templates.HomepageFile, templates.EmbeddedHomepage()),
+		site.NewTemplate(templates.NotFoundFile, templates.EmbeddedNotFound()),
+		site.NewTemplate(templates.RepositoryFile, templates.EmbeddedRepository()),
+		outputDir,
+		site.WithPartials(embeddedPartials()),
+		site.WithAssets(templates.EmbeddedAssets()),
+	)
+	require.NoError(t, err)
+
+	err = r.Render(t.Context(), site.Site{
+		Hostname: "go.nhat.io",
+		Search:   true,
+		Repositories: []site.Repository{
+			{Name: "Mock", Path: "mock", Category: "Testing"},
+			{Name: "Vanity Render", Path: "vanityrender"},
+		},
+	})
+	require.NoError(t, err)
+
+	actual := fileContent(t, filepath.Join(outputDir, "index.html"))
+
+	assert.Contains(t, actual, `<input type="search" id="search"`)
+	assert.Contains(t, actual, `<li><a href="#testing">Testing</a> <small>(1 repository)</small></li>`)
+	assert.Contains(t, actual, `<li><a href="#other">Other</a> <small>(1 repository)</small></li>`)
+	assert.Contains(t, actual, `<section class="category" id="testing">`)
+	assert.Contains(t, actual, `<h4><a href="#testing">Testing</a></h4>`)
+	assert.Contains(t, actual, `document.getElementById('search')`)
+	assert.Less(t, strings.Index(actual, `go.nhat.io/mock"`), strings.Index(actual, `go.nhat.io/vanityrender"`))
+}
+
+func TestHandlebarsRenderder_Render_LandingPages(t *testing.T) {
+	t.Parallel()
+
+	outputDir := t.TempDir()
+
+	r, err := site.NewHandlebarsRenderder(
+		site.NewTemplate("homepage.html.hbs", ""),
+		site.NewTemplate("404.html.hbs", ""),
+		site.NewTemplate("repository.html.hbs", "redirect"),
+		outputDir,
+		site.WithModuleTemplate(site.NewTemplate("module.html.hbs",
+			"{{ pageTitle }}|{{ name }}|{{ version }}|{{ superseded }}|"+
+				"{{#each majors}}{{ path }}@{{ version }}{{#if current}}*{{/if}} {{/each}}|"+
+				"{{#each submodules}}{{ path }}@{{ version }} {{/each}}",
+		)),
+	)
+	require.NoError(t, err)
+
+	err = r.Render(t.Context(), site.Site{
+		Hostname: "go.nhat.io",
+		Repositories: []site.Repository{
+			{
+				Name: "Mock",
+				Path: "mock",
+				Modules: []site.Module{
+					{Path: "mock", Version: "v1.2.0", Superseded: "mock/v2"},
+					{Path: "mock/v2", Version: "v2.1.0"},
+					{Path: "mock/sub", Version: "v0.3.0"},
+					{Path: "mock/v2/sub", Version: "v2.0.0"},
+				},
+			},
+			{
+				Name:    "Clock",
+				Path:    "clock",
+				Modules: []site.Module{{Path: "clock", Version: "v0.1.0"}},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		path           string
+		expectedResult string
+	}{
+		{path: "mock", expectedResult: "go.nhat.io/mock|Mock|v1.2.0|mock/v2|mock/v2@v2.1.0 mock@v1.2.0* |mock/sub@v0.3.0 "},
+		{path: "mock/v2", expectedResult: "go.nhat.io/mock/v2|Mock|v2.1.0||mock/v2@v2.1.0* mock@v1.2.0 |mock/v2/sub@v2.0.0 "},
+		{path: "mock/sub", expectedResult: "go.nhat.io/mock/sub|Mock|v0.3.0|||"},
+		{path: "clock", expectedResult: "go.nhat.io/clock|Clock|v0.1.0|||"},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expectedResult, fileContent(t, filepath.Join(outputDir, tc.path, "index.html")), tc.path)
+	}
+}
+
+func TestHandlebarsRenderder_Render_EmbeddedLandingPages(t *testing.T) {
+	t.Parallel()
+
+	s := site.Site{
+		Hostname: "go.nhat.io",
+		Repositories: []site.Repository{{
+			Name:        "Mock",
+			Path:        "mock",
+			Deprecated:  "use go.nhat.io/mock/v2",
+			Description: "Mock library",
+			License:     "MIT",
+			Modules: []site.Module{{
+				Path:          "mock",
+				ImportPrefix:  "mock",
+				VCS:           "git",
+				RepositoryURL: "https://github.com/nhatthm/go-mock",
+				HomeURL:       "https://github.com/nhatthm/go-mock",
+				DirectoryURL:  "https://github.com/nhatthm/go-mock/tree/master{/dir}",
+				FileURL:       "https://github.com/nhatthm/go-mock/blob/master{/dir}/{file}#L{line}",
+				Version:       "v1.2.0",
+				Readme:        "<h1 id=\"mock\">Mock</h1>\n",
+			}},
+		}},
+	}
+
+	render := func(opts ...site.RendererOption) string {
+		outputDir := t.TempDir()
+
+		r, err := site.NewHandlebarsRenderder(
+			site.NewTemplate(templates.HomepageFile, templates.EmbeddedHomepage()),
+			site.NewTemplate(templates.NotFoundFile, templates.EmbeddedNotFound()),
+			site.NewTemplate(templates.RepositoryFile, templates.EmbeddedRepository()),
+			outputDir,
+			append(opts, site.WithPartials(embeddedPartials()))...,
+		)
+		require.NoError(t, err)
+
+		err = r.Render(t.Context(), s)
+		require.NoError(t, err)
+
+		return fileContent(t, filepath.Join(outputDir, "mock", "index.html"))
+	}
+
+	redirect := render()
+	actual := render(site.WithModuleTemplate(site.NewTemplate(templates.ModuleFile, templates.EmbeddedModule())))
+
+	// The landing page has the same go-import and go-source tags as the redirect page.
+	for _, tag := range []string{"go-import", "go-source"} {
+		expected := metaTag(t, redirect, tag)
+
+		assert.Equal(t, expected, metaTag(t, actual, tag))
+	}
+
+	assert.NotContains(t, actual, `http-equiv="refresh"`)
+	assert.Contains(t, actual, `<h2>go.nhat.io/mock</h2>`)
+	assert.Contains(t, actual, `<p>Mock library</p>`)
+	assert.Contains(t, actual, `<strong>Deprecated:</strong> use go.nhat.io/mock/v2`)
+	assert.Contains(t, actual, `$ go get go.nhat.io/mock</code>`)
+	assert.Contains(t, actual, `<td>v1.2.0</td>`)
+	assert.Contains(t, actual, `<td>MIT</td>`)
+	assert.Contains(t, actual, `<a href="https://pkg.go.dev/go.nhat.io/mock" target="_blank">`)
+	assert.Contains(t, actual, `<a href="https://github.com/nhatthm/go-mock" target="_blank">`)
+	assert.Contains(t, actual, `<article class="readme"><h1 id="mock">Mock</h1>`+"\n</article>")
+	assert.NotContains(t, redirect, `<h1 id="mock">`)
+	assert.NotRegexp(t, `(?m)^ +$`, actual)
+}
+
+func TestHandlebarsRenderder_Render_DocsURL(t *testing.T) {
+	t.Parallel()
+
+	s := site.Site{
+		Hostname: "go.nhat.io",
+		Repositories: []site.Repository{
+			{
+				Path:          "mock",
+				LatestPath:    "mock/v2",
+				LatestVersion: "v2.1.0",
+				Modules: []site.Module{
+					{Path: "mock", Version: "v1.2.0", Superseded: "mock/v2"},
+					{Path: "mock/v2", Version: "v2.1.0"},
+				},
+			},
+			{
+				Path:            "clock",
+				LatestVersion:   "v0.1.0",
+				DocsURLTemplate: "https://godoc.example.com/{path}@{version}",
+				Modules:         []site.Module{{Path: "clock", Version: "v0.1.0"}},
+			},
+			{
+				Path:          "vanityrender",
+				LatestVersion: "v0.3.0",
+				DocsURL:       "https://docs.go.nhat.io/vanityrender",
+				Modules:       []site.Module{{Path: "vanityrender", Version: "v0.3.0"}},
+			},
+			{
+				Path:    "untagged",
+				Modules: []site.Module{{Path: "untagged"}},
+			},
+		},
+	}
+
+	testCases := []struct {
+		scenario         string
+		docsURLTemplate  string
+		expectedHomepage string
+		expectedModules  map[string]string
+	}{
+		{
+			scenario:         "pkg.go.dev by default",
+			expectedHomepage: "https://pkg.go.dev/go.nhat.io/mock/v2 (https://pkg.go.dev/go.nhat.io/mock) https://godoc.example.com/clock@v0.1.0 https://docs.go.nhat.io/vanityrender https://pkg.go.dev/go.nhat.io/untagged ",
+			expectedModules: map[string]string{
+				"mock":         "https://pkg.go.dev/go.nhat.io/mock|pkg.go.dev|https://pkg.go.dev/go.nhat.io/mock/v2",
+				"mock/v2":      "https://pkg.go.dev/go.nhat.io/mock/v2|pkg.go.dev|",
+				"clock":        "https://godoc.example.com/clock@v0.1.0|godoc.example.com|",
+				"vanityrender": "https://docs.go.nhat.io/vanityrender|docs.go.nhat.io|",
+				"untagged":     "https://pkg.go.dev/go.nhat.io/untagged|pkg.go.dev|",
+			},
+		},
+		{
+			scenario:         "site template",
+			docsURLTemplate:  "https://pkgsite.example.com/{module}@{version}",
+			expectedHomepage: "https://pkgsite.example.com/go.nhat.io/mock/v2@v2.1.0 (https://pkgsite.example.com/go.nhat.io/mock@v1.2.0) https://godoc.example.com/clock@v0.1.0 https://docs.go.nhat.io/vanityrender https://pkgsite.example.com/go.nhat.io/untagged ",
+			expectedModules: map[string]string{
+				"mock":         "https://pkgsite.example.com/go.nhat.io/mock@v1.2.0|pkgsite.example.com|https://pkgsite.example.com/go.nhat.io/mock/v2@v2.1.0",
+				"mock/v2":      "https://pkgsite.example.com/go.nhat.io/mock/v2@v2.1.0|pkgsite.example.com|",
+				"clock":        "https://godoc.example.com/clock@v0.1.0|godoc.example.com|",
+				"vanityrender": "https://docs.go.nhat.io/vanityrender|docs.go.nhat.io|",
+				"untagged":     "https://pkgsite.example.com/go.nhat.io/untagged|pkgsite.example.com|",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			outputDir := t.TempDir()
+
+			r, err := site.NewHandlebarsRenderder(
+				site.NewTemplate("homepage.html.hbs", "{{#each repositories}}{{ docsURL }} {{#each superseded}}({{ docsURL }}) {{/each}}{{/each}}"),
+				site.NewTemplate("404.html.hbs", ""),
+				site.NewTemplate("repository.html.hbs", "{{ docsURL }}|{{ docsHost }}|{{ supersededDocsURL }}"),
+				outputDir,
+			)
+			require.NoError(t, err)
+
+			s := s
+			s.DocsURLTemplate = tc.docsURLTemplate
+
+			err = r.Render(t.Context(), s)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expectedHomepage, fileContent(t, filepath.Join(outputDir, "index.html")))
+
+			for p, expected := range tc.expectedModules {
+				assert.Equal(t, expected, fileContent(t, filepath.Join(outputDir, p, "index.html")), p)
+			}
+		})
+	}
+}
+
+func TestHandlebarsRenderder_Render_DisableRedirect(t *testing.T) {
+	t.Parallel()
+
+	s := site.Site{
+		Hostname:        "go.nhat.io",
+		DocsURLTemplate: "https://pkgsite.example.com/{module}",
+		Repositories: []site.Repository{{
+			Path:    "mock",
+			Modules: []site.Module{{Path: "mock", ImportPrefix: "mock", VCS: "git", RepositoryURL: "https://github.com/nhatthm/go-mock"}},
+		}},
+	}
+
+	render := func(s site.Site) string {
+		outputDir := t.TempDir()
+
+		r, err := site.NewHandlebarsRenderder(
+			site.NewTemplate(templates.HomepageFile, templates.EmbeddedHomepage()),
+			site.NewTemplate(templates.NotFoundFile, templates.EmbeddedNotFound()),
+			site.NewTemplate(templates.RepositoryFile, templates.EmbeddedRepository()),
+			outputDir,
+			site.WithPartials(embeddedPartials()),
+		)
+		require.NoError(t, err)
+
+		err = r.Render(t.Context(), s)
+		require.NoError(t, err)
+
+		return fileContent(t, filepath.Join(outputDir, "mock", "index.html"))
+	}
+
+	actual := render(s)
+
+	assert.Contains(t, actual, `<meta http-equiv="refresh" content="0; url=https://pkgsite.example.com/go.nhat.io/mock">`)
+	assert.Contains(t, actual, `<a href="https://pkgsite.example.com/go.nhat.io/mock">see the package on pkgsite.example.com</a>`)
+
+	s.DisableRedirect = true
+	actual = render(s)
+
+	assert.NotContains(t, actual, `http-equiv="refresh"`)
+	assert.Contains(t, actual, `<meta name="go-import" content="go.nhat.io/mock git https://github.com/nhatthm/go-mock">`)
+	assert.Contains(t, actual, `<a href="https://pkgsite.example.com/go.nhat.io/mock">see the package on pkgsite.example.com</a>`)
+	assert.NotRegexp(t, `(?m)^ +$`, actual)
+}
+
+func TestHandlebarsRenderder_Render_RepositoryDocsURL(t *testing.T) {
+	t.Parallel()
+
+	outputDir := t.TempDir()
+
+	s := site.Site{
+		Hostname: "go.nhat.io",
+		Repositories: []site.Repository{{
+			Path:          "mock/v2",
+			LatestVersion: "v2.1.0",
+			DocsURL:       "https://docs.go.nhat.io/mock",
+			Modules: []site.Module{
+				{Path: "mock", Version: "v1.2.0", Superseded: "mock/v2", ImportPrefix: "mock", VCS: "git", RepositoryURL: "https://github.com/nhatthm/go-mock"},
+				{Path: "mock/v2", Version: "v2.1.0", ImportPrefix: "mock", VCS: "git", RepositoryURL: "https://github.com/nhatthm/go-mock"},
+			},
+		}},
+	}
+
+	r, err := site.NewHandlebarsRenderder(
+		site.NewTemplate(templates.HomepageFile, templates.EmbeddedHomepage()),
+		site.NewTemplate(templates.NotFoundFile, templates.EmbeddedNotFound()),
+		site.NewTemplate(templates.RepositoryFile, templates.EmbeddedRepository()),
+		outputDir,
+		site.WithPartials(embeddedPartials()),
+	)
+	require.NoError(t, err)
+
+	err = r.Render(t.Context(), s)
+	require.NoError(t, err)
+
+	// The docs URL of the repository is the documentation of its latest module only.
+	actual := fileContent(t, filepath.Join(outputDir, "mock", "v2", "index.html"))
+
+	assert.Contains(t, actual, `<meta http-equiv="refresh" content="0; url=https://docs.go.nhat.io/mock">`)
+	assert.Contains(t, actual, `<a href="https://docs.go.nhat.io/mock">see the package on docs.go.nhat.io</a>`)
+
+	actual = fileContent(t, filepath.Join(outputDir, "mock", "index.html"))
+
+	assert.Contains(t, actual, `<meta http-equiv="refresh" content="0; url=https://pkg.go.dev/go.nhat.io/mock">`)
+	assert.Contains(t, actual, `<a href="https://pkg.go.dev/go.nhat.io/mock">see the package on pkg.go.dev</a>`)
+}
+
+func TestHandlebarsRenderder_Render_SelfContained(t *testing.T) {
+	t.Parallel()
+
+	s := site.Site{
+		Hostname: "go.nhat.io",
+		Search:   true,
+		Repositories: []site.Repository{{
+			Name:       "Mock",
+			Path:       "mock",
+			Deprecated: "use go.nhat.io/mock/v2",
+			Modules:    []site.Module{{Path: "mock", ImportPrefix: "mock", VCS: "git", RepositoryURL: "https://github.com/nhatthm/go-mock"}},
+		}},
+	}
+
+	render := func(s site.Site) string {
+		outputDir := t.TempDir()
+
+		r, err := site.NewHandlebarsRenderder(
+			site.NewTemplate(templates.HomepageFile, templates.EmbeddedHomepage()),
+			site.NewTemplate(templates.NotFoundFile, templates.EmbeddedNotFound()),
+			site.NewTemplate(templates.RepositoryFile, templates.EmbeddedRepository()),
+			outputDir,
+			site.WithPartials(embeddedPartials()),
+			site.WithModuleTemplate(site.NewTemplate(templates.ModuleFile, templates.EmbeddedModule())),
+			site.WithAssets(templates.EmbeddedAssets()),
+		)
+		require.NoError(t, err)
+
+		err = r.Render(t.Context(), s)
+		require.NoError(t, err)
+
+		return outputDir
+	}
+
+	cdn := regexp.MustCompile(`(?:cdnjs\.cloudflare\.com|unpkg\.com|fonts\.googleapis\.com|raw\.githubusercontent\.com)`)
+	inlineScript := regexp.MustCompile(`<script>`)
+
+	// By default, the pages use the CDNs and no asset is written.
+	outputDir := render(s)
+
+	assert.NoDirExists(t, filepath.Join(outputDir, "assets"))
+	assert.Regexp(t, cdn, fileContent(t, filepath.Join(outputDir, "index.html")))
+	assert.Regexp(t, inlineScript, fileContent(t, filepath.Join(outputDir, "index.html")))
+
+	// The self-contained pages do not load anything from the CDNs.
+	s.SelfContained = true
+	outputDir = render(s)
+
+	for name, data := range templates.EmbeddedAssets() {
+		assert.Equal(t, string(bytes.TrimRight(data, "\n")), fileContent(t, filepath.Join(outputDir, "assets", name)))
+	}
+
+	for _, file := range []string{"index.html", "404.html", filepath.Join("mock", "index.html")} {
+		actual := fileContent(t, filepath.Join(outputDir, file))
+
+		assert.NotRegexp(t, cdn, actual, file)
+		assert.Contains(t, actual, `<link rel="stylesheet" href="/assets/vanityrender.css">`, file)
+		assert.NotRegexp(t, `(?m)^ +$`, actual, file)
+	}
+
+	assert.Contains(t, fileContent(t, filepath.Join(outputDir, "404.html")), `<img src="/assets/404.svg"`)
+	assert.Contains(t, fileContent(t, filepath.Join(outputDir, "index.html")), `<link rel="stylesheet" href="/assets/normalize/normalize.min.css">`)
+	assert.FileExists(t, filepath.Join(outputDir, "assets", "normalize", "LICENSE.md"))
+
+	// The scripts are loaded from a file, for a Content-Security-Policy without 'unsafe-inline'.
+	s.SelfContained = false
+	s.NoInlineScripts = true
+	outputDir = render(s)
+
+	assert.FileExists(t, filepath.Join(outputDir, "assets", "vanityrender.js"))
+	assert.NoFileExists(t, filepath.Join(outputDir, "assets", "vanityrender.css"))
+	assert.NoDirExists(t, filepath.Join(outputDir, "assets", "normalize"))
+	assert.NoDirExists(t, filepath.Join(outputDir, "assets", "font-awesome"))
+
+	actual := fileContent(t, filepath.Join(outputDir, "index.html"))
+
+	assert.NotRegexp(t, inlineScript, actual)
+	assert.Contains(t, actual, `<script src="/assets/vanityrender.js"></script>`)
+	assert.Contains(t, actual, `<script src="https://unpkg.com/tippy.js@6"></script>`)
+	assert.NotRegexp(t, `(?m)^ +$`, actual)
+}
+
 func TestHandlebarsRenderder_Render(t *testing.T) {
 	t.Parallel()
 
@@ -101,6 +766,10 @@ func TestHandlebarsRenderder_Render(t *testing.T) {
 				RepositoryURL:  "https://github.com/nhatthm/govanityrender",
 				RepositoryName: "github.com/nhatthm/govanityrender",
 				LatestVersion:  "v0.1.0",
+				Description:    "Render the vanity pages of the Go modules.",
+				Tags:           []string{"tool", "vanity"},
+				Homepage:       "https://go.nhat.io",
+				License:        "MIT",
 				Modules: []site.Module{{
 					Path:          "vanityrender",
 					ImportPrefix:  "vanityrender",
@@ -167,6 +836,71 @@ func TestHandlebarsRenderder_Render(t *testing.T) {
 					FileURL:       "https://github.com/nhatthm/testcontainers-go-registry/blob/master{/dir}/{file}#L{line}",
 				}},
 			},
+			{
+				Name:           "Majors",
+				Path:           "majors",
+				LatestPath:     "majors/v3",
+				RepositoryURL:  "https://github.com/nhatthm/majors",
+				RepositoryName: "github.com/nhatthm/majors",
+				LatestVersion:  "v3.0.1",
+				Modules: []site.Module{{
+					Path:          "majors",
+					ImportPrefix:  "majors",
+					VCS:           "git",
+					RepositoryURL: "https://github.com/nhatthm/majors",
+					HomeURL:       "https://github.com/nhatthm/majors",
+					DirectoryURL:  "https://github.com/nhatthm/majors/tree/master{/dir}",
+					FileURL:       "https://github.com/nhatthm/majors/blob/master{/dir}/{file}#L{line}",
+					Version:       "v1.4.0",
+					Superseded:    "majors/v3",
+				}, {
+					Path:          "majors/v2",
+					ImportPrefix:  "majors",
+					VCS:           "git",
+					RepositoryURL: "https://github.com/nhatthm/majors",
+					HomeURL:       "https://github.com/nhatthm/majors",
+					DirectoryURL:  "https://github.com/nhatthm/majors/tree/master{/dir}",
+					FileURL:       "https://github.com/nhatthm/majors/blob/master{/dir}/{file}#L{line}",
+					Version:       "v2.2.0",
+					Superseded:    "majors/v3",
+				}, {
+					Path:          "majors/v3",
+					ImportPrefix:  "majors",
+					VCS:           "git",
+					RepositoryURL: "https://github.com/nhatthm/majors",
+					HomeURL:       "https://github.com/nhatthm/majors",
+					DirectoryURL:  "https://github.com/nhatthm/majors/tree/master{/dir}",
+					FileURL:       "https://github.com/nhatthm/majors/blob/master{/dir}/{file}#L{line}",
+					Version:       "v3.0.1",
+				}},
+			},
+			{
+				Name:           "Mercurial",
+				Path:           "mercurial",
+				VCS:            "hg",
+				RepositoryURL:  "https://hg.example.com/mercurial",
+				RepositoryName: "https://hg.example.com/mercurial",
+				Modules: []site.Module{{
+					Path:          "mercurial",
+					ImportPrefix:  "mercurial",
+					VCS:           "hg",
+					RepositoryURL: "https://hg.example.com/mercurial",
+					HomeURL:       "https://hg.example.com/mercurial",
+				}},
+			},
+			{
+				Name:           "Proxy",
+				Path:           "proxy",
+				VCS:            "mod",
+				RepositoryURL:  "https://proxy.example.com",
+				RepositoryName: "https://proxy.example.com",
+				Modules: []site.Module{{
+					Path:          "proxy",
+					ImportPrefix:  "proxy",
+					VCS:           "mod",
+					RepositoryURL: "https://proxy.example.com",
+				}},
+			},
 			{
 				Name:           "Testcontainers Registry",
 				Path:           "testcontainers-go-registry",
@@ -187,10 +921,17 @@ func TestHandlebarsRenderder_Render(t *testing.T) {
 		},
 	}
 
-	r, err := site.NewHandlebarsRenderder(templates.EmbeddedHomepage(), templates.EmbeddedNotFound(), templates.EmbeddedRepository(), outputDir)
+	r, err := site.NewHandlebarsRenderder(
+		site.NewTemplate(templates.HomepageFile, templates.EmbeddedHomepage()),
+		site.NewTemplate(templates.NotFoundFile, templates.EmbeddedNotFound()),
+		site.NewTemplate(templates.RepositoryFile, templates.EmbeddedRepository()),
+		outputDir,
+		site.WithPartials(embeddedPartials()),
+		site.WithAssets(templates.EmbeddedAssets()),
+	)
 	require.NoError(t, err)
 
-	err = r.Render(s)
+	err = r.Render(t.Context(), s)
 	require.NoError(t, err)
 
 	assertOutput(t, "../../resources/fixtures/render_success", outputDir)
@@ -251,6 +992,25 @@ func assertOutput(t *testing.T, expectedDir, actualDir string) {
 	assert.Equal(t, expectedFiles, actualFiles)
 }
 
+func embeddedPartials() map[string]site.Template {
+	partials := make(map[string]site.Template)
+
+	for name, src := range templates.EmbeddedPartials() {
+		partials[name] = site.NewTemplate(name, src)
+	}
+
+	return partials
+}
+
+func metaTag(t *testing.T, html, name string) string {
+	t.Helper()
+
+	i := strings.Index(html, `<meta name="`+name+`"`)
+	require.GreaterOrEqualf(t, i, 0, "missing meta tag %q", name)
+
+	return html[i : i+strings.Index(html[i:], ">")+1]
+}
+
 func fileContent(t *testing.T, path string) string {
 	t.Helper()
 
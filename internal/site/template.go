@@ -0,0 +1,110 @@
+package site
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aymerick/raymond"
+
+	xerrors "go.nhat.io/vanityrender/internal/errors"
+)
+
+var parseErrorRegExp = regexp.MustCompile(`(?s)^Parse error on line (\d+):\n(.*)$`)
+
+// Template is a handlebars template.
+type Template struct {
+	// Name is the name of the template in the error messages, usually its file.
+	Name   string
+	Source string
+}
+
+// NewTemplate returns a new template.
+func NewTemplate(name, source string) Template {
+	return Template{Name: name, Source: source}
+}
+
+var _ error = (*TemplateError)(nil)
+
+// TemplateError is an error in a template.
+type TemplateError struct {
+	Name string
+	// Line is the line of the error, 0 if unknown.
+	Line int
+	Err  error
+}
+
+// Error returns the error message.
+func (e *TemplateError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", e.Name, e.Line, e.Err)
+	}
+
+	return fmt.Sprintf("%s: %s", e.Name, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *TemplateError) Unwrap() error {
+	return e.Err
+}
+
+// parseTemplate parses the template and registers the partials and the helpers.
+func parseTemplate(t Template, partials map[string]*raymond.Template) (*raymond.Template, error) {
+	tpl, err := parse(t)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, p := range partials {
+		tpl.RegisterPartialTemplate(name, p)
+	}
+
+	tpl.RegisterHelpers(helpers())
+
+	return tpl, nil
+}
+
+// parsePartials parses the partials. The partials can use each other, because they are looked up in the template that
+// is executed.
+func parsePartials(partials map[string]Template) (map[string]*raymond.Template, error) {
+	names := make([]string, 0, len(partials))
+
+	for name := range partials {
+		names = append(names, name)
+	}
+
+	// Sort the names, so the same error is reported when several partials are broken.
+	sort.Strings(names)
+
+	result := make(map[string]*raymond.Template, len(partials))
+
+	for _, name := range names {
+		tpl, err := parse(partials[name])
+		if err != nil {
+			return nil, err
+		}
+
+		result[name] = tpl
+	}
+
+	return result, nil
+}
+
+// parse parses the template and reports the errors with the name of the template and the line.
+func parse(t Template) (*raymond.Template, error) {
+	tpl, err := raymond.Parse(t.Source)
+	if err == nil {
+		return tpl, nil
+	}
+
+	tErr := &TemplateError{Name: t.Name, Err: err}
+
+	if m := parseErrorRegExp.FindStringSubmatch(err.Error()); m != nil {
+		tErr.Line, _ = strconv.Atoi(m[1]) //nolint: errcheck
+		tErr.Err = xerrors.Error(strings.ReplaceAll(strings.TrimSpace(m[2]), "\n", " "))
+	}
+
+	return nil, tErr
+}
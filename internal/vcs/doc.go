@@ -0,0 +1,2 @@
+// Package vcs provides functionalities to decorate a repository that is not hosted in git with its configured modules.
+package vcs
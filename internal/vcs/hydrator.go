@@ -0,0 +1,134 @@
+package vcs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+
+	"github.com/fatih/color"
+
+	"go.nhat.io/vanityrender/internal/site"
+)
+
+const (
+	// Git is the git version control system.
+	Git = "git"
+	// Mercurial is the mercurial version control system.
+	Mercurial = "hg"
+	// Subversion is the subversion version control system.
+	Subversion = "svn"
+	// Bazaar is the bazaar version control system.
+	Bazaar = "bzr"
+	// Fossil is the fossil version control system.
+	Fossil = "fossil"
+	// Mod is the Go module proxy protocol, the repository URL is the URL of the proxy.
+	Mod = "mod"
+)
+
+// IsValid checks whether the version control system is supported, empty means git.
+func IsValid(vcs string) bool {
+	switch vcs {
+	case "", Git, Mercurial, Subversion, Bazaar, Fossil, Mod:
+		return true
+	}
+
+	return false
+}
+
+// IsGit checks whether the version control system is git, empty means git.
+func IsGit(vcs string) bool {
+	return vcs == "" || vcs == Git
+}
+
+var _ site.Hydrator = (*Hydrator)(nil)
+
+// Hydrator is a site.Hydrator for the repositories that are not hosted in git, or that are served by a module proxy.
+// Their modules could not be discovered by cloning, so the root module and the configured submodules are used.
+type Hydrator struct {
+	output io.Writer
+}
+
+// Hydrate hydrates the repositories that are not hosted in git.
+func (h *Hydrator) Hydrate(ctx context.Context, s *site.Site) error {
+	for i := range s.Repositories {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if r := &s.Repositories[i]; !IsGit(r.VCS) {
+			h.hydrateRepository(r)
+		}
+	}
+
+	return nil
+}
+
+func (h *Hydrator) hydrateRepository(r *site.Repository) {
+	_, _ = fmt.Fprintln(h.output, color.HiBlueString("Read"), ":", r.RepositoryURL, "("+r.VCS+")") //nolint: errcheck
+
+	r.RepositoryName = r.RepositoryURL
+
+	modulePaths := make([]string, 0, len(r.Submodules)+1)
+	modulePaths = append(modulePaths, r.Path)
+
+	for _, sub := range r.Submodules {
+		modulePaths = append(modulePaths, path.Join(r.Path, sub))
+	}
+
+	sort.Strings(modulePaths)
+
+	modules := make([]site.Module, 0, len(modulePaths))
+
+	for _, p := range modulePaths {
+		_, _ = fmt.Fprintln(h.output, color.HiYellowString("Find Module"), ":", p) //nolint: errcheck
+
+		m := site.Module{
+			Path:          p,
+			ImportPrefix:  r.Path,
+			VCS:           r.VCS,
+			RepositoryURL: r.RepositoryURL,
+		}
+
+		// The proxy has no source code to browse.
+		if r.VCS != Mod {
+			m.HomeURL = r.RepositoryURL
+		}
+
+		modules = append(modules, m)
+	}
+
+	r.Modules = modules
+}
+
+// NewHydrator initiates a new site.Hydrator.
+func NewHydrator(opts ...HydratorOption) *Hydrator {
+	h := &Hydrator{
+		output: io.Discard,
+	}
+
+	for _, o := range opts {
+		o.applyHydratorOption(h)
+	}
+
+	return h
+}
+
+// HydratorOption is an option to configure Hydrator.
+type HydratorOption interface {
+	applyHydratorOption(h *Hydrator)
+}
+
+type hydratorOptionFunc func(h *Hydrator)
+
+func (f hydratorOptionFunc) applyHydratorOption(h *Hydrator) {
+	f(h)
+}
+
+// WithOutput sets the output writer.
+func WithOutput(w io.Writer) HydratorOption {
+	return hydratorOptionFunc(func(h *Hydrator) {
+		h.output = w
+	})
+}
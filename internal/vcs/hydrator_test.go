@@ -0,0 +1,119 @@
+package vcs_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/vanityrender/internal/site"
+	"go.nhat.io/vanityrender/internal/vcs"
+)
+
+func TestIsValid(t *testing.T) {
+	t.Parallel()
+
+	for _, v := range []string{"", "git", "hg", "svn", "bzr", "fossil", "mod"} {
+		assert.True(t, vcs.IsValid(v), v)
+	}
+
+	for _, v := range []string{"cvs", "Git"} {
+		assert.False(t, vcs.IsValid(v), v)
+	}
+}
+
+func TestHydrator_Hydrate(t *testing.T) {
+	t.Parallel()
+
+	s := site.Site{
+		Repositories: []site.Repository{
+			{
+				Path:          "git",
+				RepositoryURL: "https://github.com/org/git",
+			},
+			{
+				Path:          "hg",
+				VCS:           "hg",
+				RepositoryURL: "https://hg.example.com/repository",
+				Submodules:    []string{"contrib", "/b"},
+			},
+			{
+				Path:          "proxy/v2",
+				VCS:           "mod",
+				RepositoryURL: "https://proxy.example.com",
+			},
+		},
+	}
+
+	err := vcs.NewHydrator().Hydrate(t.Context(), &s)
+
+	expected := site.Site{
+		Repositories: []site.Repository{
+			{
+				Path:          "git",
+				RepositoryURL: "https://github.com/org/git",
+			},
+			{
+				Path:           "hg",
+				VCS:            "hg",
+				RepositoryURL:  "https://hg.example.com/repository",
+				RepositoryName: "https://hg.example.com/repository",
+				Submodules:     []string{"contrib", "/b"},
+				Modules: []site.Module{
+					{
+						Path:          "hg",
+						ImportPrefix:  "hg",
+						VCS:           "hg",
+						RepositoryURL: "https://hg.example.com/repository",
+						HomeURL:       "https://hg.example.com/repository",
+					},
+					{
+						Path:          "hg/b",
+						ImportPrefix:  "hg",
+						VCS:           "hg",
+						RepositoryURL: "https://hg.example.com/repository",
+						HomeURL:       "https://hg.example.com/repository",
+					},
+					{
+						Path:          "hg/contrib",
+						ImportPrefix:  "hg",
+						VCS:           "hg",
+						RepositoryURL: "https://hg.example.com/repository",
+						HomeURL:       "https://hg.example.com/repository",
+					},
+				},
+			},
+			{
+				Path:           "proxy/v2",
+				VCS:            "mod",
+				RepositoryURL:  "https://proxy.example.com",
+				RepositoryName: "https://proxy.example.com",
+				Modules: []site.Module{
+					{
+						Path:          "proxy/v2",
+						ImportPrefix:  "proxy/v2",
+						VCS:           "mod",
+						RepositoryURL: "https://proxy.example.com",
+					},
+				},
+			},
+		},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, s)
+}
+
+func TestHydrator_Hydrate_Canceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	s := site.Site{Repositories: []site.Repository{{Path: "hg", VCS: "hg"}}}
+
+	err := vcs.NewHydrator().Hydrate(ctx, &s)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, s.Repositories[0].Modules)
+}
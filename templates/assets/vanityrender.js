@@ -0,0 +1,37 @@
+/*
+ * The scripts of the homepage. They are inlined in the page, or loaded from this file when the inline scripts are
+ * disabled, for a Content-Security-Policy that forbids them.
+ */
+(function () {
+    'use strict';
+
+    // The tooltips of the deprecated repositories, when tippy.js is loaded from the CDN.
+    if (typeof window.tippy === 'function') {
+        window.tippy('.deprecated', {
+            content(reference) {
+                return reference.getAttribute('data-tooltip');
+            }
+        });
+    }
+
+    const search = document.getElementById('search');
+
+    if (search === null) {
+        return;
+    }
+
+    search.addEventListener('input', function (event) {
+        const query = event.target.value.trim().toLowerCase();
+
+        document.querySelectorAll('section.category').forEach(function (section) {
+            let visible = 0;
+
+            section.querySelectorAll('tbody tr').forEach(function (row) {
+                row.hidden = !row.textContent.toLowerCase().includes(query);
+                visible += row.hidden ? 0 : 1;
+            });
+
+            section.hidden = visible === 0;
+        });
+    });
+})();
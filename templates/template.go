@@ -1,7 +1,31 @@
 // Package templates provides the embedded templates.
 package templates
 
-import _ "embed"
+import (
+	"embed"
+	"io/fs"
+	"path"
+	"strings"
+
+	"go.nhat.io/vanityrender/internal/must"
+)
+
+const (
+	// HomepageFile is the file name of the homepage template.
+	HomepageFile = "homepage.html.hbs"
+	// NotFoundFile is the file name of the 404 Not Found template.
+	NotFoundFile = "404.html.hbs"
+	// RepositoryFile is the file name of the repository template.
+	RepositoryFile = "repository.html.hbs"
+	// ModuleFile is the file name of the module landing page template.
+	ModuleFile = "module.html.hbs"
+	// PartialsDir is the directory of the partials, the name of a partial is its path without the .hbs extension.
+	PartialsDir = "partials"
+	// PartialExt is the file extension of the partials.
+	PartialExt = ".hbs"
+	// AssetsDir is the directory of the stylesheets, scripts and images of the self-contained pages.
+	AssetsDir = "assets"
+)
 
 //go:embed homepage.html.hbs
 var homepageTpl string
@@ -12,6 +36,15 @@ var notFoundTpl string
 //go:embed repository.html.hbs
 var repositoryTpl string
 
+//go:embed module.html.hbs
+var moduleTpl string
+
+//go:embed partials
+var partials embed.FS
+
+//go:embed assets
+var assets embed.FS
+
 // EmbeddedHomepage provides the homepage template.
 func EmbeddedHomepage() string {
 	return homepageTpl
@@ -26,3 +59,62 @@ func EmbeddedNotFound() string {
 func EmbeddedRepository() string {
 	return repositoryTpl
 }
+
+// EmbeddedModule provides the module landing page template.
+func EmbeddedModule() string {
+	return moduleTpl
+}
+
+// EmbeddedPartials provides the partials by name, e.g. footer for partials/footer.hbs.
+func EmbeddedPartials() map[string]string {
+	result := make(map[string]string)
+
+	err := fs.WalkDir(partials, PartialsDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(p) != PartialExt {
+			return err
+		}
+
+		data, err := partials.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		result[PartialName(strings.TrimPrefix(p, PartialsDir+"/"))] = string(data)
+
+		return nil
+	})
+
+	must.NoError(err)
+
+	return result
+}
+
+// EmbeddedAssets provides the assets by their paths relative to the assets directory, e.g. vanityrender.css.
+func EmbeddedAssets() map[string][]byte {
+	result := make(map[string][]byte)
+
+	err := fs.WalkDir(assets, AssetsDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		data, err := assets.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		result[strings.TrimPrefix(p, AssetsDir+"/")] = data
+
+		return nil
+	})
+
+	must.NoError(err)
+
+	return result
+}
+
+// PartialName returns the name of a partial from its path relative to the partials directory, e.g. footer for
+// footer.hbs.
+func PartialName(p string) string {
+	return strings.TrimSuffix(p, PartialExt)
+}